@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/metrics"
+	"github.com/diwise/api-snowdepth/pkg/tracing"
+	"github.com/diwise/messaging-golang/pkg/messaging"
+	messagingtracing "github.com/diwise/messaging-golang/pkg/messaging/tracing"
+)
+
+// defaultExchange is the exchange that the messaging library publishes topic messages on
+const defaultExchange = "iot-msg-exchange-topic"
+
+// brokerConfig is the messaging configuration together with the port of the broker, which the
+// messaging library does not let us configure
+type brokerConfig struct {
+	messaging.Config
+	port string
+}
+
+// loadBrokerConfig reads the port of the broker from SNOWDEPTH_BROKER_PORT. The messaging
+// library always connects on the default port 5672.
+func loadBrokerConfig(cfg messaging.Config) (brokerConfig, error) {
+	broker := brokerConfig{Config: cfg, port: "5672"}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_BROKER_PORT"); ok {
+		port, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || port == 0 {
+			return broker, fmt.Errorf("invalid value %q for SNOWDEPTH_BROKER_PORT: must be a port number", value)
+		}
+		broker.port = value
+	}
+
+	return broker, nil
+}
+
+// url returns the URL of the broker. The user name and password are escaped, so that they
+// may contain any characters.
+func (cfg brokerConfig) url() string {
+	u := url.URL{
+		Scheme: "amqp",
+		User:   url.UserPassword(cfg.User, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, cfg.port),
+		Path:   "/",
+	}
+	return u.String()
+}
+
+// batchConfig controls the buffering receiver. A size of one stores every measurement as
+// soon as it has been received.
+type batchConfig struct {
+	size        int
+	delay       time.Duration
+	maxInFlight int
+	queue       string
+	exchange    string
+}
+
+// loadBatchConfig reads the batch configuration from SNOWDEPTH_INGEST_BATCH_SIZE,
+// SNOWDEPTH_INGEST_BATCH_DELAY, SNOWDEPTH_INGEST_MAX_IN_FLIGHT, SNOWDEPTH_INGEST_QUEUE and
+// SNOWDEPTH_INGEST_EXCHANGE
+func loadBatchConfig(serviceName string) (batchConfig, error) {
+	cfg := batchConfig{delay: time.Second, queue: serviceName + ".snowdepth", exchange: defaultExchange}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_BATCH_SIZE"); ok {
+		if cfg.size, err = strconv.Atoi(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_BATCH_SIZE: %w", value, err)
+		}
+	}
+
+	if cfg.size < 0 || cfg.size > database.MaxBatchSize {
+		return cfg, fmt.Errorf("SNOWDEPTH_INGEST_BATCH_SIZE must be between 0 and %d", database.MaxBatchSize)
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_BATCH_DELAY"); ok {
+		if cfg.delay, err = time.ParseDuration(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_BATCH_DELAY: %w", value, err)
+		}
+	}
+
+	if cfg.delay <= 0 {
+		return cfg, fmt.Errorf("SNOWDEPTH_INGEST_BATCH_DELAY must be positive")
+	}
+
+	// Without batching, measurements are stored one at a time by the same receiver
+	if cfg.size == 0 {
+		cfg.size = 1
+	}
+
+	// Allow enough unacknowledged messages to fill a few batches while one is being stored
+	cfg.maxInFlight = cfg.size * 4
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_MAX_IN_FLIGHT"); ok {
+		if cfg.maxInFlight, err = strconv.Atoi(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_MAX_IN_FLIGHT: %w", value, err)
+		}
+	}
+
+	if cfg.maxInFlight < cfg.size {
+		return cfg, fmt.Errorf("SNOWDEPTH_INGEST_MAX_IN_FLIGHT must be at least the batch size %d", cfg.size)
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_QUEUE"); ok && value != "" {
+		cfg.queue = value
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_EXCHANGE"); ok && value != "" {
+		cfg.exchange = value
+	}
+
+	return cfg, nil
+}
+
+// batchReceiver consumes snowdepth telemetry with manual acknowledgements and stores it in
+// batches. Messages are acknowledged once the batch that contains them has been committed,
+// or once they have been stored as dead letters, and the broker never delivers more than
+// maxInFlight unacknowledged messages.
+type batchReceiver struct {
+	db           database.Datastore
+	cfg          batchConfig
+	policy       retryPolicy
+	drainTimeout time.Duration
+	counters     *ingestCounters
+	logger       zerolog.Logger
+}
+
+// startBatchReceiver consumes messages with the routing key in the background until ctx is
+// cancelled, reconnecting to the broker if the connection is lost. The buffered deliveries
+// are stored within drainTimeout once ctx is cancelled, and the returned channel is closed
+// when the receiver has disconnected.
+func startBatchReceiver(ctx context.Context, broker brokerConfig, routingKey string, db database.Datastore, cfg batchConfig, policy retryPolicy, drainTimeout time.Duration, logger zerolog.Logger) <-chan struct{} {
+	r := &batchReceiver{
+		db:           db,
+		cfg:          cfg,
+		policy:       policy,
+		drainTimeout: drainTimeout,
+		counters:     &ingestCounters{},
+		logger: logger.With().
+			Str("queue", cfg.queue).
+			Str("exchange", cfg.exchange).
+			Int("batchSize", cfg.size).
+			Int("maxInFlight", cfg.maxInFlight).
+			Logger(),
+	}
+
+	r.logger.Info().Dur("batchDelay", cfg.delay).Msg("starting batch receiver")
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			err := r.run(ctx, broker, routingKey)
+			if ctx.Err() != nil {
+				return
+			}
+
+			r.logger.Error().Err(err).Msg("batch receiver stopped, reconnecting ...")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}()
+
+	return done
+}
+
+// run connects to the broker and consumes messages until the connection is lost or ctx is cancelled
+func (r *batchReceiver) run(ctx context.Context, broker brokerConfig, routingKey string) error {
+	conn, err := amqp.Dial(broker.url())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	if err = channel.Qos(r.cfg.maxInFlight, 0, false); err != nil {
+		return err
+	}
+
+	// Declared with the same arguments as in the messaging library
+	if err = channel.ExchangeDeclare(r.cfg.exchange, amqp.ExchangeTopic, false, false, false, false, nil); err != nil {
+		return err
+	}
+
+	// A durable, shared queue keeps unacknowledged messages across restarts and lets several
+	// instances share the load
+	queue, err := channel.QueueDeclare(r.cfg.queue, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = channel.QueueBind(queue.Name, routingKey, r.cfg.exchange, false, nil); err != nil {
+		return err
+	}
+
+	deliveries, err := channel.Consume(queue.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Info().Msg("successfully registered as a consumer")
+
+	return r.consume(ctx, deliveries)
+}
+
+// consume buffers deliveries and flushes them when the batch is full, or when the oldest
+// buffered delivery has waited for the configured delay
+func (r *batchReceiver) consume(ctx context.Context, deliveries <-chan amqp.Delivery) error {
+	batch := make([]amqp.Delivery, 0, r.cfg.size)
+
+	timer := time.NewTimer(r.cfg.delay)
+	stopTimer := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+	}
+	stopTimer()
+
+	flush := func() {
+		stopTimer()
+		if len(batch) > 0 {
+			r.flush(ctx, r.policy, batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-deliveries:
+			if !ok {
+				// Unacknowledged messages are redelivered by the broker once the channel is closed
+				return errors.New("delivery channel closed")
+			}
+
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(r.cfg.delay)
+			}
+
+			if len(batch) >= r.cfg.size {
+				flush()
+			}
+		case <-timer.C:
+			r.flush(ctx, r.policy, batch)
+			batch = batch[:0]
+		case <-ctx.Done():
+			stopTimer()
+			r.drain(batch)
+			return ctx.Err()
+		}
+	}
+}
+
+// drain stores the buffered deliveries when the receiver is stopped. Since ctx has been
+// cancelled by then, they are stored with a context that expires after the drain timeout,
+// and are not retried so that shutdown is not held up. Deliveries that have not been
+// settled when the connection is closed are redelivered by the broker.
+func (r *batchReceiver) drain(deliveries []amqp.Delivery) {
+	if len(deliveries) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.drainTimeout)
+	defer cancel()
+
+	r.logger.Info().Int("count", len(deliveries)).Msg("storing buffered deliveries before stopping")
+	r.flush(ctx, retryPolicy{attempts: 1}, deliveries)
+}
+
+// flush stores a batch of deliveries and acknowledges them. A batch that fails for a reason
+// that may pass is retried according to the retry policy. Deliveries that can not be stored
+// are acknowledged once they have been stored as dead letters, and are requeued if that
+// fails too, so that they are not lost while the database is unavailable.
+func (r *batchReceiver) flush(ctx context.Context, policy retryPolicy, deliveries []amqp.Delivery) {
+	start := time.Now()
+
+	ctx, span := startBatchSpan(ctx, deliveries)
+	var failure error
+	defer func() { tracing.End(span, failure) }()
+
+	batch := make([]database.NewMeasurement, 0, len(deliveries))
+	accepted := make([]amqp.Delivery, 0, len(deliveries))
+
+	for _, d := range deliveries {
+		m, err := database.ParseTelemetry(d.Body)
+		if err != nil {
+			atomic.AddUint64(&r.counters.failures, 1)
+			metrics.RecordIngestFailure(metrics.FailureMalformed)
+			r.logger.Error().Err(err).Str("body", string(d.Body)).Msg("failed to unmarshal message")
+			r.deadLetter(ctx, []amqp.Delivery{d}, database.DeadLetterMalformed, 0, err)
+			continue
+		}
+
+		batch = append(batch, m)
+		accepted = append(accepted, d)
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var results []database.IngestResult
+
+	attempts, err := policy.do(ctx, r.logger, func() (err error) {
+		results, err = r.db.AddSnowdepthMeasurements(ctx, batch)
+		return err
+	})
+
+	if err != nil {
+		atomic.AddUint64(&r.counters.failures, uint64(len(accepted)))
+		failure = err
+		r.logger.Error().Err(err).Int("count", len(accepted)).Int("attempts", attempts).Dict("totals", r.counters.dict()).Msg("failed to add batch of snowdepth measurements")
+
+		if ctx.Err() != nil {
+			// Leave the batch to another consumer when shutting down
+			for _, d := range accepted {
+				r.settle(d, false, true)
+			}
+			return
+		}
+
+		r.deadLetter(ctx, accepted, deadLetterReason(err), attempts, err)
+		return
+	}
+
+	for idx, result := range results {
+		logIngestResult(r.logger, r.counters, result.Outcome, result.Err)
+
+		if database.IsHandled(result.Outcome, result.Err) {
+			r.settle(accepted[idx], true, false)
+		} else {
+			r.deadLetter(ctx, accepted[idx:idx+1], deadLetterReason(result.Err), attempts, result.Err)
+		}
+	}
+
+	r.logger.Info().Int("count", len(accepted)).Dur("elapsed", time.Since(start)).Msg("stored batch of snowdepth measurements")
+}
+
+// startBatchSpan starts the span of a batch. A batch holds messages from many traces, so
+// the span is the root of a trace of its own, with links to the trace context that has
+// been extracted from the headers of each delivery.
+func startBatchSpan(ctx context.Context, deliveries []amqp.Delivery) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(deliveries))
+
+	for _, d := range deliveries {
+		sc := trace.SpanContextFromContext(messagingtracing.ExtractAMQPHeaders(context.Background(), d.Headers))
+		if sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+
+	return tracing.Start(ctx, "ingest snowdepth batch",
+		trace.WithNewRoot(),
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("snowdepth.batch_size", len(deliveries))),
+	)
+}
+
+// deadLetter stores deliveries as dead letters and acknowledges them. Since requeued
+// deliveries are kept by the broker, storing is not retried, and the remaining deliveries
+// are requeued as soon as one of them can not be stored.
+func (r *batchReceiver) deadLetter(ctx context.Context, deliveries []amqp.Delivery, reason string, attempts int, cause error) {
+	stored := true
+
+	for _, d := range deliveries {
+		if stored {
+			stored = storeDeadLetter(ctx, r.db, retryPolicy{attempts: 1}, r.counters, r.logger, d, reason, attempts, cause)
+		}
+		r.settle(d, stored, !stored)
+	}
+}
+
+// settle acknowledges a delivery, or rejects it with or without requeueing it
+func (r *batchReceiver) settle(d amqp.Delivery, ack, requeue bool) {
+	var err error
+
+	if ack {
+		err = d.Ack(false)
+	} else {
+		err = d.Nack(false, requeue)
+	}
+
+	if err != nil {
+		r.logger.Error().Err(err).Uint64("deliveryTag", d.DeliveryTag).Msg("failed to settle delivery")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/messaging-golang/pkg/messaging"
+)
+
+// deviceStatusChangedMessage is published when a device goes stale, offline or back online
+type deviceStatusChangedMessage struct {
+	DeviceID         string    `json:"deviceID"`
+	Status           string    `json:"status"`
+	PreviousStatus   string    `json:"previousStatus"`
+	LastSeen         time.Time `json:"lastSeen"`
+	ExpectedInterval int64     `json:"expectedInterval"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+func (m *deviceStatusChangedMessage) ContentType() string {
+	return "application/json"
+}
+
+func (m *deviceStatusChangedMessage) TopicName() string {
+	return "device-status-changed"
+}
+
+// startHealthMonitor updates the statuses of the devices in the background at the interval
+// configured in the policy, until ctx is cancelled, and publishes a message for each change
+func startHealthMonitor(ctx context.Context, db database.Datastore, policy database.HealthPolicy, messenger messaging.MsgContext, logger zerolog.Logger) {
+	logger = logger.With().
+		Str("job", "health").
+		Dur("defaultInterval", policy.DefaultInterval).
+		Float64("staleFactor", policy.StaleFactor).
+		Float64("offlineFactor", policy.OfflineFactor).
+		Logger()
+
+	logger.Info().Dur("interval", policy.CheckInterval).Msg("starting device health monitor")
+
+	go func() {
+		ticker := time.NewTicker(policy.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			updateDeviceStatuses(ctx, db, policy, messenger, logger)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func updateDeviceStatuses(ctx context.Context, db database.Datastore, policy database.HealthPolicy, messenger messaging.MsgContext, logger zerolog.Logger) {
+	result, err := db.UpdateDeviceStatuses(ctx, policy)
+	if err != nil {
+		logger.Error().Err(err).Bool("retryable", database.IsRetryable(err)).Msg("failed to update device statuses")
+		return
+	}
+
+	if result.Skipped {
+		logger.Debug().Msg("device statuses are already being updated by another instance")
+		return
+	}
+
+	for _, change := range result.Changes {
+		sublog := logger.With().
+			Str("device", change.Device).
+			Str("status", change.Status).
+			Str("previousStatus", change.Previous).
+			Time("lastSeen", change.LastSeen).
+			Logger()
+
+		// The first status of a device that was known before its health was tracked is not a
+		// change that anyone needs to be told about
+		if change.Previous == "" {
+			sublog.Debug().Msg("determined device status")
+			continue
+		}
+
+		sublog.Info().Msg("device status changed")
+
+		msg := &deviceStatusChangedMessage{
+			DeviceID:         change.Device,
+			Status:           change.Status,
+			PreviousStatus:   change.Previous,
+			LastSeen:         change.LastSeen,
+			ExpectedInterval: int64(change.ExpectedInterval / time.Second),
+			Timestamp:        change.ChangedAt,
+		}
+
+		if err := messenger.PublishOnTopic(ctx, msg); err != nil {
+			sublog.Error().Err(err).Msg("failed to publish device status change")
+		}
+	}
+}
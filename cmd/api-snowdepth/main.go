@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/diwise/api-snowdepth/pkg/alerts"
 	"github.com/diwise/api-snowdepth/pkg/database"
 	"github.com/diwise/api-snowdepth/pkg/handler"
+	"github.com/diwise/api-snowdepth/pkg/metrics"
+	"github.com/diwise/api-snowdepth/pkg/probes"
+	"github.com/diwise/api-snowdepth/pkg/tracing"
 	"github.com/diwise/messaging-golang/pkg/messaging"
 	"github.com/diwise/messaging-golang/pkg/messaging/telemetry"
 )
@@ -17,19 +27,168 @@ func main() {
 
 	logger := log.With().Str("service", strings.ToLower(serviceName)).Logger()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:], logger))
+	}
+
 	logger.Info().Msg("starting up ...")
 
+	tracingConfig, err := tracing.LoadConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid tracing configuration")
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), serviceName, tracingConfig)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to initialize tracing")
+	}
+
 	config := messaging.LoadConfiguration(serviceName, logger)
 	messenger, _ := messaging.Initialize(config)
 
-	defer messenger.Close()
+	retention, err := database.LoadRetentionPolicy()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid retention policy")
+	}
+
+	health, err := database.LoadHealthPolicy()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid device health policy")
+	}
+
+	alertConfig, err := alerts.LoadConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid alert configuration")
+	}
+
+	outbox, err := loadOutboxConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid outbox configuration")
+	}
+
+	readiness, err := probes.LoadConfig()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid readiness configuration")
+	}
+
+	broker, err := loadBrokerConfig(config)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid messaging configuration")
+	}
+
+	// The messaging library refuses to start without RABBITMQ_HOST unless messaging has been
+	// disabled with RABBITMQ_DISABLED
+	if config.Host != "" {
+		readiness.Checks = append(readiness.Checks, messagingCheck(broker))
+	} else {
+		logger.Warn().Msg("messaging is disabled, no telemetry will be received and no events will be published")
+		readiness.Checks = append(readiness.Checks, messagingDisabledCheck())
+	}
+
+	shutdownTimeout, err := loadShutdownTimeout()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid shutdown timeout")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	db, err := database.NewDatastore(logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create datastore")
+	}
+
+	rules, err := db.GetAlertRules(ctx)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load alert rules")
+	}
+
+	if err := alerts.CheckRules(alertConfig, rules); err != nil {
+		logger.Fatal().Err(err).Msg("invalid alert configuration")
+	}
+
+	webhooks := alerts.NewWebhooks(alertConfig, logger)
+
+	db = metrics.NewInstrumentedDatastore(db)
+	db = tracing.NewTracedDatastore(db)
 
-	db, _ := database.NewDatabaseConnection(logger)
+	if retention.Enabled() {
+		startRetentionJob(ctx, db, retention, logger)
+	}
+
+	startHealthMonitor(ctx, db, health, messenger, logger)
+	relayStopped := startOutboxRelay(ctx, db, messenger, webhooks, outbox, logger)
+
+	batchConfig, err := loadBatchConfig(serviceName)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid batch configuration")
+	}
+
+	retry, err := loadRetryPolicy()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid retry policy")
+	}
+
+	// Create the server before consuming telemetry so that a bad api key configuration stops
+	// the service before any message has been received
+	server, err := handler.CreateServer(db, messenger, alertConfig, readiness, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create the http server")
+	}
 
 	topicName := (&telemetry.Snowdepth{}).TopicName()
-	logger.Info().Msgf("registering message handler for topic %s", topicName)
-	messenger.RegisterTopicMessageHandler(topicName, createSnowdepthReceiver(db))
+	var receiverStopped <-chan struct{}
+
+	// Telemetry is consumed with manual acknowledgements rather than through the messaging
+	// library, which acknowledges messages when they are received, so that no message is
+	// lost if the service stops before it has been stored
+	if config.Host != "" {
+		receiverStopped = startBatchReceiver(ctx, broker, topicName, db, batchConfig, retry, shutdownTimeout, logger)
+	}
+
+	go func() {
+		logger.Info().Str("addr", server.Addr).Msg("listening for incoming connections")
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal().Err(err).Msg("failed to start listening on port")
+		}
+	}()
+
+	<-ctx.Done()
+	// Let a second signal terminate the process immediately
+	stop()
+
+	logger.Info().Dur("timeout", shutdownTimeout).Msg("shutting down ...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Stop consuming and wait for the received messages to be stored
+	if receiverStopped != nil {
+		if err := waitFor(shutdownCtx, receiverStopped); err != nil {
+			logger.Error().Err(err).Msg("receiver did not stop in time")
+		}
+	}
+
+	// Stop accepting requests and wait for the ones in progress
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to shut down http server")
+	}
+
+	// Publish the events and alerts for what has been stored
+	if err := waitFor(shutdownCtx, relayStopped); err == nil {
+		relayOutbox(shutdownCtx, db, messenger, webhooks, outbox, logger.With().Str("job", "outbox").Logger())
+	}
+
+	messenger.Close()
+
+	if err := db.Close(); err != nil {
+		logger.Error().Err(err).Msg("failed to close datastore")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to export the remaining spans")
+	}
 
-	logger.Info().Msg("calling CreateRouterAndStartServing")
-	handler.CreateRouterAndStartServing(db, messenger, logger)
+	logger.Info().Msg("shutdown complete")
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+const migrateUsage = "usage: api-snowdepth migrate status|up|down"
+
+// runMigrateCommand handles the migrate subcommand and returns the process exit code
+func runMigrateCommand(args []string, logger zerolog.Logger) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := database.NewMigrator(logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to connect to database")
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to read migration status")
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED")
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Description, applied)
+		}
+		w.Flush()
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to apply migrations")
+			return 1
+		}
+		logger.Info().Int("applied", count).Msg("database is up to date")
+	case "down":
+		version, err := migrator.Down()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to roll back migration")
+			return 1
+		}
+		if version == 0 {
+			logger.Info().Msg("no migrations to roll back")
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/alerts"
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/messaging-golang/pkg/messaging"
+)
+
+// outboxConfig controls how often and how many messages are relayed from the outbox
+type outboxConfig struct {
+	interval  time.Duration
+	batchSize int
+}
+
+// loadOutboxConfig reads the outbox configuration from SNOWDEPTH_OUTBOX_INTERVAL and
+// SNOWDEPTH_OUTBOX_BATCH_SIZE
+func loadOutboxConfig() (outboxConfig, error) {
+	cfg := outboxConfig{interval: time.Second, batchSize: 100}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_OUTBOX_INTERVAL"); ok {
+		if cfg.interval, err = time.ParseDuration(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_OUTBOX_INTERVAL: %w", value, err)
+		}
+	}
+
+	if cfg.interval <= 0 {
+		return cfg, fmt.Errorf("SNOWDEPTH_OUTBOX_INTERVAL must be positive")
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_OUTBOX_BATCH_SIZE"); ok {
+		if cfg.batchSize, err = strconv.Atoi(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_OUTBOX_BATCH_SIZE: %w", value, err)
+		}
+	}
+
+	if cfg.batchSize < 1 {
+		return cfg, fmt.Errorf("SNOWDEPTH_OUTBOX_BATCH_SIZE must be at least 1")
+	}
+
+	return cfg, nil
+}
+
+// startOutboxRelay publishes the messages in the outbox in the background, until ctx is
+// cancelled. The outbox is drained at every interval, and the returned channel is closed
+// when the relay has stopped.
+func startOutboxRelay(ctx context.Context, db database.Datastore, messenger messaging.MsgContext, webhooks *alerts.Webhooks, cfg outboxConfig, logger zerolog.Logger) <-chan struct{} {
+	logger = logger.With().Str("job", "outbox").Logger()
+
+	logger.Info().Dur("interval", cfg.interval).Int("batchSize", cfg.batchSize).Msg("starting outbox relay")
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(cfg.interval)
+		defer ticker.Stop()
+
+		for {
+			relayOutbox(ctx, db, messenger, webhooks, cfg, logger)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return done
+}
+
+// relayOutbox publishes the messages in the outbox on the message bus, and posts the alerts
+// that are addressed to webhooks
+func relayOutbox(ctx context.Context, db database.Datastore, messenger messaging.MsgContext, webhooks *alerts.Webhooks, cfg outboxConfig, logger zerolog.Logger) {
+	for ctx.Err() == nil {
+		result, err := db.RelayOutbox(ctx, cfg.batchSize, func(ctx context.Context, msg database.OutboxMessage) error {
+			if msg.Webhook != "" {
+				err := webhooks.Deliver(ctx, msg)
+				if err != nil {
+					logger.Warn().Err(err).
+						Uint64("id", msg.ID).
+						Int("attempts", msg.Attempts+1).
+						Msg("failed to deliver alert to webhook")
+				}
+				return err
+			}
+
+			err := messenger.PublishOnTopic(ctx, msg)
+			if err != nil {
+				logger.Error().Err(err).
+					Uint64("id", msg.ID).
+					Str("topic", msg.TopicName()).
+					Int("attempts", msg.Attempts+1).
+					Msg("failed to publish message from outbox")
+			}
+			return err
+		})
+
+		if err != nil {
+			logger.Error().Err(err).Bool("retryable", database.IsRetryable(err)).Msg("failed to relay messages from outbox")
+			return
+		}
+
+		if result.Published > 0 {
+			logger.Debug().Int("published", result.Published).Msg("relayed messages from outbox")
+		}
+
+		// Keep going while there may be more messages to publish
+		if result.Failed || result.Published < cfg.batchSize {
+			return
+		}
+	}
+}
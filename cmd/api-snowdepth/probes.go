@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/diwise/api-snowdepth/pkg/probes"
+)
+
+// messagingCheck creates a check that connects to the message broker. The messaging library
+// terminates the service if its own connection is lost, so the check verifies that the
+// broker can still be reached, for example to publish the outbox or to reconnect the batch
+// receiver.
+func messagingCheck(broker brokerConfig) probes.Check {
+	return probes.Check{
+		Name: "messaging",
+		Run: func(ctx context.Context) error {
+			timeout := 2 * time.Second
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+
+			conn, err := amqp.DialConfig(broker.url(), amqp.Config{Dial: amqp.DefaultDial(timeout)})
+			if err != nil {
+				return err
+			}
+
+			return conn.Close()
+		},
+	}
+}
+
+// messagingDisabledCheck creates a check that always fails, so that an instance that runs
+// with messaging disabled, and therefore neither receives telemetry nor publishes events, is
+// never reported as ready
+func messagingDisabledCheck() probes.Check {
+	return probes.Check{
+		Name: "messaging",
+		Run: func(ctx context.Context) error {
+			return errors.New("messaging is disabled")
+		},
+	}
+}
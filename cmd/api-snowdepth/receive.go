@@ -2,39 +2,122 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"sync/atomic"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rs/zerolog"
 
 	"github.com/diwise/api-snowdepth/pkg/database"
-	"github.com/diwise/messaging-golang/pkg/messaging"
-	"github.com/diwise/messaging-golang/pkg/messaging/telemetry"
+	"github.com/diwise/api-snowdepth/pkg/metrics"
+	"github.com/diwise/api-snowdepth/pkg/models"
 )
 
-func createSnowdepthReceiver(db database.Datastore) messaging.TopicMessageHandler {
-	return func(ctx context.Context, msg amqp.Delivery, logger zerolog.Logger) {
+// ingestCounters keeps running totals of what happened to received measurements, so that
+// duplicates, conflicts and quarantined measurements can be told apart from actual failures
+type ingestCounters struct {
+	inserted     uint64
+	duplicates   uint64
+	conflicts    uint64
+	quarantined  uint64
+	failures     uint64
+	deadLettered uint64
+}
 
-		logger.Info().Str("body", string(msg.Body)).Msg("message received from queue")
+func (c *ingestCounters) add(outcome database.IngestOutcome, err error) {
+	switch {
+	case outcome == database.IngestQuarantined:
+		atomic.AddUint64(&c.quarantined, 1)
+	case errors.Is(err, database.ErrConflictingMeasurement):
+		atomic.AddUint64(&c.conflicts, 1)
+	case errors.Is(err, database.ErrDuplicateMeasurement):
+		atomic.AddUint64(&c.duplicates, 1)
+	case err != nil:
+		atomic.AddUint64(&c.failures, 1)
+	case outcome == database.IngestInserted:
+		atomic.AddUint64(&c.inserted, 1)
+	case outcome == database.IngestDuplicate:
+		atomic.AddUint64(&c.duplicates, 1)
+	default:
+		atomic.AddUint64(&c.conflicts, 1)
+	}
+}
 
-		depth := &telemetry.Snowdepth{}
-		err := json.Unmarshal(msg.Body, depth)
+func (c *ingestCounters) dict() *zerolog.Event {
+	return zerolog.Dict().
+		Uint64("inserted", atomic.LoadUint64(&c.inserted)).
+		Uint64("duplicates", atomic.LoadUint64(&c.duplicates)).
+		Uint64("conflicts", atomic.LoadUint64(&c.conflicts)).
+		Uint64("quarantined", atomic.LoadUint64(&c.quarantined)).
+		Uint64("failures", atomic.LoadUint64(&c.failures)).
+		Uint64("deadLettered", atomic.LoadUint64(&c.deadLettered))
+}
 
-		if err != nil {
-			logger.Error().Err(err).Msg("failed to unmarshal message")
-			return
-		}
+// deadLetterReason returns the reason why a measurement that could not be stored is dead-lettered
+func deadLetterReason(err error) string {
+	if errors.Is(err, database.ErrValidation) {
+		return database.DeadLetterRejected
+	}
+	return database.DeadLetterRetriesExhausted
+}
+
+// storeDeadLetter adds a received message to the dead letter table and reports whether it
+// was stored. A message that can not be stored there either is logged in full, since the
+// caller may not be able to recover it.
+func storeDeadLetter(ctx context.Context, db database.Datastore, policy retryPolicy, counters *ingestCounters, logger zerolog.Logger, msg amqp.Delivery, reason string, attempts int, cause error) bool {
+	letter := database.NewDeadLetter{
+		RoutingKey:  msg.RoutingKey,
+		ContentType: msg.ContentType,
+		Body:        msg.Body,
+		Reason:      reason,
+		Err:         cause,
+		Attempts:    attempts,
+	}
+
+	var stored *models.DeadLetter
 
-		// TODO: Propagate database errors, catch and log them here ...
-		_, err = db.AddSnowdepthMeasurement(
-			&depth.Origin.Device,
-			depth.Origin.Latitude, depth.Origin.Longitude,
-			float64(depth.Depth),
-			depth.Timestamp,
-		)
+	_, err := policy.do(ctx, logger, func() (err error) {
+		stored, err = db.AddDeadLetter(ctx, letter)
+		return err
+	})
 
-		if err != nil {
-			logger.Error().Err(err).Msg("failed to add snowdepth measurement")
+	if err != nil {
+		logger.Error().Err(err).Str("reason", reason).Str("body", string(msg.Body)).Msg("failed to store dead letter")
+		return false
+	}
+
+	atomic.AddUint64(&counters.deadLettered, 1)
+	metrics.RecordDeadLetter(reason)
+	logger.Warn().Uint64("deadLetter", stored.ID).Str("reason", reason).Dict("totals", counters.dict()).Msg("stored message as a dead letter")
+
+	return true
+}
+
+// logIngestResult counts and logs the outcome of adding a received measurement
+func logIngestResult(logger zerolog.Logger, counters *ingestCounters, outcome database.IngestOutcome, err error) {
+	counters.add(outcome, err)
+
+	if err == nil {
+		if outcome != database.IngestInserted {
+			logger.Info().Str("outcome", outcome.String()).Dict("totals", counters.dict()).Msg("received a duplicate snowdepth measurement")
 		}
+		return
+	}
+
+	logger = logger.With().Dict("totals", counters.dict()).Logger()
+
+	switch {
+	case outcome == database.IngestQuarantined:
+		logger.Warn().Err(err).Msg("quarantined snowdepth measurement")
+	case errors.Is(err, database.ErrConflictingMeasurement):
+		logger.Warn().Err(err).Bool("retryable", false).Msg("rejected conflicting snowdepth measurement")
+	case errors.Is(err, database.ErrDuplicateMeasurement):
+		logger.Info().Err(err).Msg("ignoring duplicate snowdepth measurement")
+	case errors.Is(err, database.ErrValidation):
+		logger.Warn().Err(err).Bool("retryable", false).Msg("rejected snowdepth measurement")
+	case database.IsRetryable(err):
+		logger.Error().Err(err).Bool("retryable", true).Msg("failed to add snowdepth measurement")
+	default:
+		logger.Error().Err(err).Bool("retryable", false).Msg("failed to add snowdepth measurement")
 	}
 }
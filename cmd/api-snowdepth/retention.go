@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+// startRetentionJob applies the retention policy in the background, once at startup and
+// then at the interval configured in the policy, until ctx is cancelled
+func startRetentionJob(ctx context.Context, db database.Datastore, policy database.RetentionPolicy, logger zerolog.Logger) {
+	logger = logger.With().
+		Str("job", "retention").
+		Dur("rawRetention", policy.RawRetention).
+		Int("purgeAfterSeasons", policy.PurgeAfterSeasons).
+		Logger()
+
+	logger.Info().Dur("interval", policy.Interval).Msg("starting retention job")
+
+	go func() {
+		ticker := time.NewTicker(policy.Interval)
+		defer ticker.Stop()
+
+		for {
+			applyRetention(ctx, db, policy, logger)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func applyRetention(ctx context.Context, db database.Datastore, policy database.RetentionPolicy, logger zerolog.Logger) {
+	start := time.Now()
+
+	result, err := db.ApplyRetention(ctx, policy)
+	if err != nil {
+		logger.Error().Err(err).Bool("retryable", database.IsRetryable(err)).Msg("failed to apply retention policy")
+		return
+	}
+
+	if result.Skipped {
+		logger.Info().Msg("retention policy is already being applied by another instance")
+		return
+	}
+
+	logger.Info().
+		Int64("downsampled", result.Downsampled).
+		Int64("purged", result.Purged).
+		Dur("elapsed", time.Since(start)).
+		Msg("applied retention policy")
+}
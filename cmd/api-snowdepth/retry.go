@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+// retryPolicy controls how received measurements are retried when they can not be stored
+// for a transient reason, such as the database being unavailable
+type retryPolicy struct {
+	attempts int
+	delay    time.Duration
+	maxDelay time.Duration
+}
+
+// loadRetryPolicy reads the retry policy from SNOWDEPTH_INGEST_RETRY_ATTEMPTS,
+// SNOWDEPTH_INGEST_RETRY_DELAY and SNOWDEPTH_INGEST_RETRY_MAX_DELAY
+func loadRetryPolicy() (retryPolicy, error) {
+	policy := retryPolicy{attempts: 5, delay: 500 * time.Millisecond, maxDelay: 10 * time.Second}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_RETRY_ATTEMPTS"); ok {
+		if policy.attempts, err = strconv.Atoi(value); err != nil {
+			return policy, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_RETRY_ATTEMPTS: %w", value, err)
+		}
+	}
+
+	if policy.attempts < 1 {
+		return policy, fmt.Errorf("SNOWDEPTH_INGEST_RETRY_ATTEMPTS must be at least 1")
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_RETRY_DELAY"); ok {
+		if policy.delay, err = time.ParseDuration(value); err != nil {
+			return policy, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_RETRY_DELAY: %w", value, err)
+		}
+	}
+
+	if policy.delay <= 0 {
+		return policy, fmt.Errorf("SNOWDEPTH_INGEST_RETRY_DELAY must be positive")
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_INGEST_RETRY_MAX_DELAY"); ok {
+		if policy.maxDelay, err = time.ParseDuration(value); err != nil {
+			return policy, fmt.Errorf("invalid value %q for SNOWDEPTH_INGEST_RETRY_MAX_DELAY: %w", value, err)
+		}
+	}
+
+	if policy.maxDelay < policy.delay {
+		return policy, fmt.Errorf("SNOWDEPTH_INGEST_RETRY_MAX_DELAY must be at least SNOWDEPTH_INGEST_RETRY_DELAY")
+	}
+
+	return policy, nil
+}
+
+// do calls fn until it succeeds, fails with an error that is not retryable, or has been
+// called the configured number of times. The delay between attempts is doubled every time,
+// up to the maximum delay. It returns the number of attempts and the last error.
+func (p retryPolicy) do(ctx context.Context, logger zerolog.Logger, fn func() error) (int, error) {
+	delay := p.delay
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !database.IsRetryable(err) || attempt >= p.attempts {
+			return attempt, err
+		}
+
+		logger.Warn().Err(err).Int("attempt", attempt).Dur("delay", delay).Msg("retrying after a transient failure")
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(delay):
+		}
+
+		if delay *= 2; delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+}
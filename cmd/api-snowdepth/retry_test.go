@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	unavailable := fmt.Errorf("%w: connection refused", database.ErrUnavailable)
+	invalid := fmt.Errorf("%w: bad timestamp", database.ErrValidation)
+
+	tests := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{"succeeds at once", []error{nil}, 1, nil},
+		{"succeeds after transient failures", []error{unavailable, unavailable, nil}, 3, nil},
+		{"does not retry permanent failures", []error{invalid}, 1, database.ErrValidation},
+		{"stops retrying permanent failures", []error{unavailable, invalid}, 2, database.ErrValidation},
+		{"gives up after the last attempt", []error{unavailable, unavailable, unavailable, unavailable}, 3, database.ErrUnavailable},
+	}
+
+	policy := retryPolicy{attempts: 3, delay: time.Millisecond, maxDelay: 2 * time.Millisecond}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+
+			attempts, err := policy.do(context.Background(), zerolog.Nop(), func() error {
+				calls++
+				return tc.errs[calls-1]
+			})
+
+			if attempts != tc.expectedAttempts || calls != tc.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d with %d calls", tc.expectedAttempts, attempts, calls)
+			}
+
+			if tc.expectedErr == nil && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	policy := retryPolicy{attempts: 5, delay: time.Hour, maxDelay: time.Hour}
+
+	attempts, err := policy.do(ctx, zerolog.Nop(), func() error {
+		return database.ErrUnavailable
+	})
+
+	if attempts != 1 || !errors.Is(err, database.ErrUnavailable) {
+		t.Errorf("expected a single attempt that failed, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestLoadRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected retryPolicy
+		fails    bool
+	}{
+		{
+			name:     "defaults",
+			expected: retryPolicy{attempts: 5, delay: 500 * time.Millisecond, maxDelay: 10 * time.Second},
+		},
+		{
+			name:     "configured",
+			env:      map[string]string{"SNOWDEPTH_INGEST_RETRY_ATTEMPTS": "2", "SNOWDEPTH_INGEST_RETRY_DELAY": "1s", "SNOWDEPTH_INGEST_RETRY_MAX_DELAY": "1m"},
+			expected: retryPolicy{attempts: 2, delay: time.Second, maxDelay: time.Minute},
+		},
+		{name: "no attempts", env: map[string]string{"SNOWDEPTH_INGEST_RETRY_ATTEMPTS": "0"}, fails: true},
+		{name: "invalid attempts", env: map[string]string{"SNOWDEPTH_INGEST_RETRY_ATTEMPTS": "many"}, fails: true},
+		{name: "no delay", env: map[string]string{"SNOWDEPTH_INGEST_RETRY_DELAY": "0s"}, fails: true},
+		{name: "max delay below delay", env: map[string]string{"SNOWDEPTH_INGEST_RETRY_DELAY": "1m", "SNOWDEPTH_INGEST_RETRY_MAX_DELAY": "1s"}, fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			policy, err := loadRetryPolicy()
+
+			if tc.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", policy)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if policy != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// loadShutdownTimeout reads the time allowed for a graceful shutdown from SNOWDEPTH_SHUTDOWN_TIMEOUT
+func loadShutdownTimeout() (time.Duration, error) {
+	timeout := 20 * time.Second
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_SHUTDOWN_TIMEOUT"); ok {
+		if timeout, err = time.ParseDuration(value); err != nil {
+			return timeout, fmt.Errorf("invalid value %q for SNOWDEPTH_SHUTDOWN_TIMEOUT: %w", value, err)
+		}
+	}
+
+	if timeout <= 0 {
+		return timeout, fmt.Errorf("SNOWDEPTH_SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	return timeout, nil
+}
+
+// waitFor waits until done is closed or ctx expires
+func waitFor(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
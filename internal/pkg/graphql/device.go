@@ -0,0 +1,14 @@
+package graphql
+
+import "github.com/diwise/api-snowdepth/pkg/models"
+
+// Device is the federated device entity. The health of a device is resolved on demand, unless
+// it was already loaded together with the device.
+type Device struct {
+	ID string `json:"id"`
+
+	health *models.DeviceHealth
+	loaded bool
+}
+
+func (Device) IsEntity() {}
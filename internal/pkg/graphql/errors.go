@@ -0,0 +1,94 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/diwise/api-snowdepth/pkg/auth"
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+// Error codes that are added to the extensions of errors returned to clients
+const (
+	CodeBadUserInput         = "BAD_USER_INPUT"
+	CodeQuarantined          = "MEASUREMENT_QUARANTINED"
+	CodeDuplicateMeasurement = "DUPLICATE_MEASUREMENT"
+	CodeConflict             = "CONFLICTING_MEASUREMENT"
+	CodeNotFound             = "NOT_FOUND"
+	CodeUnauthenticated      = "UNAUTHENTICATED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeInternalServerError  = "INTERNAL_SERVER_ERROR"
+)
+
+// ErrorPresenter adds an extension code to errors returned from resolvers, so that clients
+// can tell invalid input apart from duplicates and temporary failures
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlerr := graphql.DefaultErrorPresenter(ctx, err)
+
+	if _, ok := gqlerr.Extensions["code"]; ok || gqlerr.Unwrap() == nil {
+		// Either the resolver set its own code, or this is an error created by gqlgen itself
+		return gqlerr
+	}
+
+	if gqlerr.Extensions == nil {
+		gqlerr.Extensions = map[string]interface{}{}
+	}
+
+	gqlerr.Extensions["code"] = errorCode(err)
+
+	return gqlerr
+}
+
+func errorCode(err error) string {
+	var rejected *database.RejectedError
+
+	switch {
+	case errors.As(err, &rejected):
+		return CodeQuarantined
+	case errors.Is(err, database.ErrValidation):
+		return CodeBadUserInput
+	case errors.Is(err, database.ErrDuplicateMeasurement):
+		return CodeDuplicateMeasurement
+	case errors.Is(err, database.ErrConflictingMeasurement):
+		return CodeConflict
+	case errors.Is(err, database.ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, database.ErrUnavailable):
+		return CodeServiceUnavailable
+	}
+
+	return CodeInternalServerError
+}
+
+func newBadUserInputError(format string, args ...interface{}) error {
+	return &gqlerror.Error{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]interface{}{"code": CodeBadUserInput},
+	}
+}
+
+// requireRole returns an error unless the request was authenticated with an identity that
+// has been granted the role
+func requireRole(ctx context.Context, role string) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return &gqlerror.Error{
+			Message:    "this operation requires an api key",
+			Extensions: map[string]interface{}{"code": CodeUnauthenticated},
+		}
+	}
+
+	if !identity.HasRole(role) {
+		return &gqlerror.Error{
+			Message:    fmt.Sprintf("this operation requires the %s role", role),
+			Extensions: map[string]interface{}{"code": CodeForbidden},
+		}
+	}
+
+	return nil
+}
@@ -2,15 +2,133 @@
 
 package graphql
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type Telemetry interface {
 	IsTelemetry()
 }
 
-type Device struct {
-	ID string `json:"id"`
+// Raises an alert when the depth at a device or a site reaches the threshold. Rules without a
+// device or a site apply to all measurements.
+type AlertRule struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Device    *Device        `json:"device"`
+	Site      *AlertSite     `json:"site"`
+	Direction AlertDirection `json:"direction"`
+	Threshold float64        `json:"threshold"`
+	// How far the depth must move back past the threshold before the rule can trigger again
+	Hysteresis float64 `json:"hysteresis"`
+	// The minimum number of seconds between two alerts from the rule for the same device
+	Cooldown   int     `json:"cooldown"`
+	Enabled    bool    `json:"enabled"`
+	WebhookURL *string `json:"webhookUrl"`
+}
+
+type AlertRuleInput struct {
+	Name       string         `json:"name"`
+	Device     *string        `json:"device"`
+	Site       *Circle        `json:"site"`
+	Direction  AlertDirection `json:"direction"`
+	Threshold  float64        `json:"threshold"`
+	Hysteresis *float64       `json:"hysteresis"`
+	Cooldown   *int           `json:"cooldown"`
+	Enabled    *bool          `json:"enabled"`
+	WebhookURL *string        `json:"webhookUrl"`
+}
+
+// A circle with a radius in meters
+type AlertSite struct {
+	Center *WGS84Position `json:"center"`
+	Radius float64        `json:"radius"`
+}
+
+// Either a bounding box or a circle
+type Area struct {
+	Box    *BoundingBox `json:"box"`
+	Circle *Circle      `json:"circle"`
+}
+
+// The values of a measurement before or after a change
+type AuditedSnowdepth struct {
+	Pos       *WGS84Position `json:"pos"`
+	When      string         `json:"when"`
+	Depth     float64        `json:"depth"`
+	Retracted bool           `json:"retracted"`
+}
+
+type BoundingBox struct {
+	SouthWest *MeasurementPosition `json:"southWest"`
+	NorthEast *MeasurementPosition `json:"northEast"`
+}
+
+// A circle with a radius in meters
+type Circle struct {
+	Center *MeasurementPosition `json:"center"`
+	Radius float64              `json:"radius"`
+}
+
+// A received message that could not be stored
+type DeadLetter struct {
+	ID          string           `json:"id"`
+	ReceivedAt  string           `json:"receivedAt"`
+	RoutingKey  string           `json:"routingKey"`
+	ContentType string           `json:"contentType"`
+	Payload     string           `json:"payload"`
+	Reason      DeadLetterReason `json:"reason"`
+	// The error from the last attempt to store or replay the message
+	Error      string  `json:"error"`
+	Attempts   int     `json:"attempts"`
+	ReplayedAt *string `json:"replayedAt"`
+}
+
+// The result of replaying a dead letter. error is set if the measurement still could not be stored.
+type DeadLetterReplay struct {
+	DeadLetter *DeadLetter    `json:"deadLetter"`
+	Outcome    *IngestOutcome `json:"outcome"`
+	Snowdepth  *Snowdepth     `json:"snowdepth"`
+	Error      *string        `json:"error"`
+}
+
+// Converts the raw values from a device to snow depths as raw * scale + offset, or as
+// (mountingHeight - raw) * scale + offset for sensors that report the distance to the surface
+type DeviceCalibration struct {
+	ID             string   `json:"id"`
+	Device         *Device  `json:"device"`
+	MountingHeight *float64 `json:"mountingHeight"`
+	Offset         float64  `json:"offset"`
+	Scale          float64  `json:"scale"`
+	ValidFrom      string   `json:"validFrom"`
+	// Not set for calibrations that are valid until further notice
+	ValidTo *string `json:"validTo"`
+}
+
+type DeviceCalibrationChange struct {
+	Calibration *DeviceCalibration `json:"calibration"`
+	// The number of stored measurements that had their depth recomputed
+	Recomputed int `json:"recomputed"`
+}
+
+type DeviceCalibrationInput struct {
+	Device         string   `json:"device"`
+	MountingHeight *float64 `json:"mountingHeight"`
+	Offset         *float64 `json:"offset"`
+	Scale          *float64 `json:"scale"`
+	ValidFrom      string   `json:"validFrom"`
+	ValidTo        *string  `json:"validTo"`
 }
 
-func (Device) IsEntity() {}
+type DeviceHealth struct {
+	Status          DeviceStatus `json:"status"`
+	StatusChangedAt string       `json:"statusChangedAt"`
+	LastSeen        string       `json:"lastSeen"`
+	// The number of seconds between reports that is expected from the device, if it differs from the default
+	ExpectedInterval *int `json:"expectedInterval"`
+}
 
 type MeasurementPosition struct {
 	Lon float64 `json:"lon"`
@@ -20,6 +138,8 @@ type MeasurementPosition struct {
 type NewSnowdepthMeasurement struct {
 	Pos   *MeasurementPosition `json:"pos"`
 	Depth float64              `json:"depth"`
+	// The person that reported the measurement, recorded in the audit trail
+	ReportedBy *string `json:"reportedBy"`
 }
 
 type Origin struct {
@@ -27,16 +147,374 @@ type Origin struct {
 	Pos    *WGS84Position `json:"pos"`
 }
 
+// A measurement that was rejected by a validation rule
+type QuarantinedSnowdepth struct {
+	ID         string  `json:"id"`
+	RejectedAt string  `json:"rejectedAt"`
+	From       *Origin `json:"from"`
+	When       string  `json:"when"`
+	Depth      float64 `json:"depth"`
+	RawDepth   float64 `json:"rawDepth"`
+	Rule       string  `json:"rule"`
+	Reason     string  `json:"reason"`
+}
+
 type Snowdepth struct {
-	From   *Origin `json:"from"`
-	When   string  `json:"when"`
-	Depth  float64 `json:"depth"`
-	Manual *bool   `json:"manual"`
+	// Not set for measurements that have been downsampled
+	ID    *string `json:"id"`
+	From  *Origin `json:"from"`
+	When  string  `json:"when"`
+	Depth float64 `json:"depth"`
+	// The value reported by the sensor, if the depth was computed with a device calibration
+	RawDepth *float64 `json:"rawDepth"`
+	Manual   *bool    `json:"manual"`
 }
 
 func (Snowdepth) IsTelemetry() {}
 
+type SnowdepthAuditEntry struct {
+	ID          string         `json:"id"`
+	At          string         `json:"at"`
+	Operation   AuditOperation `json:"operation"`
+	Measurement string         `json:"measurement"`
+	Actor       string         `json:"actor"`
+	// The name of the API key or token that the change was made with
+	Identity  string            `json:"identity"`
+	ClientIP  string            `json:"clientIP"`
+	UserAgent string            `json:"userAgent"`
+	Before    *AuditedSnowdepth `json:"before"`
+	After     *AuditedSnowdepth `json:"after"`
+}
+
+// A new depth and/or position for a measurement, together with who made the change and why
+type SnowdepthCorrection struct {
+	ID     string               `json:"id"`
+	Depth  *float64             `json:"depth"`
+	Pos    *MeasurementPosition `json:"pos"`
+	Reason string               `json:"reason"`
+}
+
+type SnowdepthRetraction struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type SnowdepthStatistics struct {
+	Device *Device `json:"device"`
+	Start  string  `json:"start"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Count  int     `json:"count"`
+	Last   float64 `json:"last"`
+}
+
 type WGS84Position struct {
 	Lon float64 `json:"lon"`
 	Lat float64 `json:"lat"`
 }
+
+type AlertDirection string
+
+const (
+	AlertDirectionAbove AlertDirection = "ABOVE"
+	AlertDirectionBelow AlertDirection = "BELOW"
+)
+
+var AllAlertDirection = []AlertDirection{
+	AlertDirectionAbove,
+	AlertDirectionBelow,
+}
+
+func (e AlertDirection) IsValid() bool {
+	switch e {
+	case AlertDirectionAbove, AlertDirectionBelow:
+		return true
+	}
+	return false
+}
+
+func (e AlertDirection) String() string {
+	return string(e)
+}
+
+func (e *AlertDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertDirection", str)
+	}
+	return nil
+}
+
+func (e AlertDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AuditOperation string
+
+const (
+	AuditOperationAdd     AuditOperation = "ADD"
+	AuditOperationCorrect AuditOperation = "CORRECT"
+	AuditOperationRetract AuditOperation = "RETRACT"
+)
+
+var AllAuditOperation = []AuditOperation{
+	AuditOperationAdd,
+	AuditOperationCorrect,
+	AuditOperationRetract,
+}
+
+func (e AuditOperation) IsValid() bool {
+	switch e {
+	case AuditOperationAdd, AuditOperationCorrect, AuditOperationRetract:
+		return true
+	}
+	return false
+}
+
+func (e AuditOperation) String() string {
+	return string(e)
+}
+
+func (e *AuditOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditOperation", str)
+	}
+	return nil
+}
+
+func (e AuditOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DeadLetterReason string
+
+const (
+	// The message could not be parsed as snowdepth telemetry
+	DeadLetterReasonMalformed DeadLetterReason = "MALFORMED"
+	// The measurement was invalid
+	DeadLetterReasonRejected DeadLetterReason = "REJECTED"
+	// Storing the measurement kept failing for a transient reason
+	DeadLetterReasonRetriesExhausted DeadLetterReason = "RETRIES_EXHAUSTED"
+)
+
+var AllDeadLetterReason = []DeadLetterReason{
+	DeadLetterReasonMalformed,
+	DeadLetterReasonRejected,
+	DeadLetterReasonRetriesExhausted,
+}
+
+func (e DeadLetterReason) IsValid() bool {
+	switch e {
+	case DeadLetterReasonMalformed, DeadLetterReasonRejected, DeadLetterReasonRetriesExhausted:
+		return true
+	}
+	return false
+}
+
+func (e DeadLetterReason) String() string {
+	return string(e)
+}
+
+func (e *DeadLetterReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeadLetterReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeadLetterReason", str)
+	}
+	return nil
+}
+
+func (e DeadLetterReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DeviceStatus string
+
+const (
+	DeviceStatusOnline  DeviceStatus = "ONLINE"
+	DeviceStatusStale   DeviceStatus = "STALE"
+	DeviceStatusOffline DeviceStatus = "OFFLINE"
+	// The status has not been determined yet
+	DeviceStatusUnknown DeviceStatus = "UNKNOWN"
+)
+
+var AllDeviceStatus = []DeviceStatus{
+	DeviceStatusOnline,
+	DeviceStatusStale,
+	DeviceStatusOffline,
+	DeviceStatusUnknown,
+}
+
+func (e DeviceStatus) IsValid() bool {
+	switch e {
+	case DeviceStatusOnline, DeviceStatusStale, DeviceStatusOffline, DeviceStatusUnknown:
+		return true
+	}
+	return false
+}
+
+func (e DeviceStatus) String() string {
+	return string(e)
+}
+
+func (e *DeviceStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeviceStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeviceStatus", str)
+	}
+	return nil
+}
+
+func (e DeviceStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type IngestOutcome string
+
+const (
+	IngestOutcomeInserted        IngestOutcome = "INSERTED"
+	IngestOutcomeDuplicate       IngestOutcome = "DUPLICATE"
+	IngestOutcomeConflictIgnored IngestOutcome = "CONFLICT_IGNORED"
+	IngestOutcomeOverwritten     IngestOutcome = "OVERWRITTEN"
+	IngestOutcomeQuarantined     IngestOutcome = "QUARANTINED"
+)
+
+var AllIngestOutcome = []IngestOutcome{
+	IngestOutcomeInserted,
+	IngestOutcomeDuplicate,
+	IngestOutcomeConflictIgnored,
+	IngestOutcomeOverwritten,
+	IngestOutcomeQuarantined,
+}
+
+func (e IngestOutcome) IsValid() bool {
+	switch e {
+	case IngestOutcomeInserted, IngestOutcomeDuplicate, IngestOutcomeConflictIgnored, IngestOutcomeOverwritten, IngestOutcomeQuarantined:
+		return true
+	}
+	return false
+}
+
+func (e IngestOutcome) String() string {
+	return string(e)
+}
+
+func (e *IngestOutcome) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IngestOutcome(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IngestOutcome", str)
+	}
+	return nil
+}
+
+func (e IngestOutcome) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "ASC"
+	SortOrderDesc SortOrder = "DESC"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderAsc,
+	SortOrderDesc,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderAsc, SortOrderDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StatisticsInterval string
+
+const (
+	StatisticsIntervalHour StatisticsInterval = "HOUR"
+	StatisticsIntervalDay  StatisticsInterval = "DAY"
+)
+
+var AllStatisticsInterval = []StatisticsInterval{
+	StatisticsIntervalHour,
+	StatisticsIntervalDay,
+}
+
+func (e StatisticsInterval) IsValid() bool {
+	switch e {
+	case StatisticsIntervalHour, StatisticsIntervalDay:
+		return true
+	}
+	return false
+}
+
+func (e StatisticsInterval) String() string {
+	return string(e)
+}
+
+func (e *StatisticsInterval) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StatisticsInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StatisticsInterval", str)
+	}
+	return nil
+}
+
+func (e StatisticsInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
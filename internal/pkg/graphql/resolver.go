@@ -3,16 +3,21 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"math"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/diwise/api-snowdepth/pkg/alerts"
+	"github.com/diwise/api-snowdepth/pkg/auth"
 	"github.com/diwise/api-snowdepth/pkg/database"
 	"github.com/diwise/api-snowdepth/pkg/models"
 )
 
-type Resolver struct{}
-
-func (r *entityResolver) FindDeviceByID(ctx context.Context, id string) (*Device, error) {
-	return &Device{ID: id}, nil
+type Resolver struct {
+	// Alerts is used to check the webhooks of the alert rules that are added or updated
+	Alerts alerts.Config
 }
 
 func convertDatabaseRecordToGQL(measurement *models.Snowdepth) *Snowdepth {
@@ -24,10 +29,20 @@ func convertDatabaseRecordToGQL(measurement *models.Snowdepth) *Snowdepth {
 					Lon: measurement.Longitude,
 				},
 			},
-			When:  measurement.Timestamp,
+			When:  measurement.Timestamp.UTC().Format(time.RFC3339),
 			Depth: math.Round(float64(measurement.Depth*10)) / 10,
 		}
 
+		if measurement.ID != 0 {
+			id := strconv.FormatUint(uint64(measurement.ID), 10)
+			depth.ID = &id
+		}
+
+		if measurement.CalibrationID != nil {
+			rawDepth := math.Round(float64(measurement.RawDepth*10)) / 10
+			depth.RawDepth = &rawDepth
+		}
+
 		if len(measurement.Device) == 0 {
 			depth.Manual = &[]bool{true}[0] // <- You may Google that little nugget of beauty ...
 		} else {
@@ -47,20 +62,237 @@ func (r *mutationResolver) AddSnowdepthMeasurement(ctx context.Context, input Ne
 		return nil, err
 	}
 
-	measurement, err := db.AddManualSnowdepthMeasurement(input.Pos.Lat, input.Pos.Lon, input.Depth)
-	return convertDatabaseRecordToGQL(measurement), err
+	if input.ReportedBy != nil {
+		source := database.AuditSourceFromContext(ctx)
+		source.Actor = *input.ReportedBy
+		ctx = database.WithAuditSource(ctx, source)
+	}
+
+	measurement, err := db.AddManualSnowdepthMeasurement(ctx, input.Pos.Lat, input.Pos.Lon, input.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDatabaseRecordToGQL(measurement), nil
+}
+
+func parseID(kind, value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || id == 0 {
+		return 0, newBadUserInputError("%q is not a valid %s id", value, kind)
+	}
+
+	return uint(id), nil
+}
+
+// changedBy returns the name of the identity that edits a measurement, after checking that
+// it has been granted the admin role
+func changedBy(ctx context.Context) (string, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return "", err
+	}
+
+	identity, _ := auth.FromContext(ctx)
+	return identity.Name, nil
+}
+
+func (r *mutationResolver) CorrectSnowdepthMeasurement(ctx context.Context, input SnowdepthCorrection) (*Snowdepth, error) {
+	actor, err := changedBy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseID("measurement", input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	correction := database.SnowdepthCorrection{
+		MeasurementChange: database.MeasurementChange{ChangedBy: actor, Reason: input.Reason},
+		Depth:             input.Depth,
+	}
+
+	if input.Pos != nil {
+		correction.Latitude = &input.Pos.Lat
+		correction.Longitude = &input.Pos.Lon
+	}
+
+	measurement, err := db.CorrectSnowdepthMeasurement(ctx, id, correction)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDatabaseRecordToGQL(measurement), nil
+}
+
+func (r *mutationResolver) RetractSnowdepthMeasurement(ctx context.Context, input SnowdepthRetraction) (*Snowdepth, error) {
+	actor, err := changedBy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseID("measurement", input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := database.MeasurementChange{ChangedBy: actor, Reason: input.Reason}
+
+	measurement, err := db.RetractSnowdepthMeasurement(ctx, id, change)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDatabaseRecordToGQL(measurement), nil
 }
 
-func (r *queryResolver) Snowdepths(ctx context.Context) ([]*Snowdepth, error) {
+func parseDateTime(value *string) (time.Time, error) {
+	if value == nil {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return time.Time{}, newBadUserInputError("failed to parse %s as an RFC3339 timestamp", *value)
+	}
+
+	return t, nil
+}
+
+func parseLimit(limit *int) (uint64, error) {
+	if limit == nil {
+		return 0, nil
+	}
+
+	if *limit < 0 {
+		return 0, newBadUserInputError("limit must be non negative")
+	}
+
+	return uint64(*limit), nil
+}
+
+func newArea(within *Area) (*database.Area, error) {
+	if within == nil {
+		return nil, nil
+	}
+
+	area := &database.Area{}
+
+	if within.Box != nil {
+		if within.Box.SouthWest == nil || within.Box.NorthEast == nil {
+			return nil, newBadUserInputError("a bounding box must have both a south west and a north east corner")
+		}
+		area.Box = &database.BoundingBox{
+			South: within.Box.SouthWest.Lat,
+			West:  within.Box.SouthWest.Lon,
+			North: within.Box.NorthEast.Lat,
+			East:  within.Box.NorthEast.Lon,
+		}
+	}
+
+	if within.Circle != nil {
+		if within.Circle.Center == nil {
+			return nil, newBadUserInputError("a circle must have a center")
+		}
+		area.Circle = &database.Circle{
+			Latitude:  within.Circle.Center.Lat,
+			Longitude: within.Circle.Center.Lon,
+			Radius:    within.Circle.Radius,
+		}
+	}
+
+	if err := area.Validate(); err != nil {
+		return nil, err
+	}
+
+	return area, nil
+}
+
+func newSnowdepthQuery(from, to, device *string, area *database.Area, order *SortOrder, limit *int) (database.SnowdepthQuery, error) {
+	var err error
+	query := database.SnowdepthQuery{Device: device, Area: area}
+
+	if query.From, err = parseDateTime(from); err != nil {
+		return query, err
+	}
+
+	if query.To, err = parseDateTime(to); err != nil {
+		return query, err
+	}
+
+	if order != nil && *order == SortOrderDesc {
+		query.Order = database.SortDescending
+	}
+
+	query.Limit, err = parseLimit(limit)
+
+	return query, err
+}
+
+// applyQuery filters the latest measurements on the area of the query, orders them by time
+// and applies the limit, the same way as GetSnowdepthHistory does for a time span
+func applyQuery(depths []models.Snowdepth, query database.SnowdepthQuery) []models.Snowdepth {
+	if query.Area != nil {
+		depths = query.Area.Filter(depths)
+	}
+
+	sort.SliceStable(depths, func(i, j int) bool {
+		if query.Order == database.SortDescending {
+			return depths[i].Timestamp.After(depths[j].Timestamp)
+		}
+		return depths[i].Timestamp.Before(depths[j].Timestamp)
+	})
+
+	if query.Limit > 0 && uint64(len(depths)) > query.Limit {
+		depths = depths[:query.Limit]
+	}
+
+	return depths
+}
+
+func (r *queryResolver) Snowdepths(ctx context.Context, from *string, to *string, device *string, within *Area, order *SortOrder, limit *int) ([]*Snowdepth, error) {
 	db, err := database.GetFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	depths, err := db.GetLatestSnowdepths()
+	area, err := newArea(within)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := newSnowdepthQuery(from, to, device, area, order, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var depths []models.Snowdepth
+
+	if from == nil && to == nil {
+		if device != nil {
+			depths, err = db.GetLatestSnowdepthsForDevice(ctx, *device)
+		} else {
+			depths, err = db.GetLatestSnowdepths(ctx)
+		}
+		if err == nil {
+			depths = applyQuery(depths, query)
+		}
+	} else {
+		depths, err = db.GetSnowdepthHistory(ctx, query)
+	}
 
 	if err != nil {
-		panic("Failed to query latest snowdepths: " + err.Error())
+		return nil, err
 	}
 
 	depthcount := len(depths)
@@ -78,10 +310,759 @@ func (r *queryResolver) Snowdepths(ctx context.Context) ([]*Snowdepth, error) {
 	return gqldepths, nil
 }
 
+func (r *queryResolver) SnowdepthStatistics(ctx context.Context, device *string, from string, to string, interval *StatisticsInterval) ([]*SnowdepthStatistics, error) {
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := parseDateTime(&from)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseDateTime(&to)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketSize := database.AggregateHourly
+	if interval != nil && *interval == StatisticsIntervalDay {
+		bucketSize = database.AggregateDaily
+	}
+
+	stats, err := db.GetSnowdepthStatistics(ctx, device, start, end, bucketSize)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlstats := make([]*SnowdepthStatistics, 0, len(stats))
+
+	for _, v := range stats {
+		s := &SnowdepthStatistics{
+			Start: v.Start.UTC().Format(time.RFC3339),
+			Min:   math.Round(v.Min*10) / 10,
+			Max:   math.Round(v.Max*10) / 10,
+			Mean:  math.Round(v.Mean*10) / 10,
+			Count: int(v.Count),
+			Last:  math.Round(v.Last*10) / 10,
+		}
+
+		if len(v.Device) > 0 {
+			s.Device = &Device{ID: v.Device}
+		}
+
+		gqlstats = append(gqlstats, s)
+	}
+
+	return gqlstats, nil
+}
+
+func convertAuditedSnowdepthToGQL(values *models.AuditedSnowdepth) *AuditedSnowdepth {
+	if values == nil {
+		return nil
+	}
+
+	return &AuditedSnowdepth{
+		Pos:       &WGS84Position{Lat: values.Latitude, Lon: values.Longitude},
+		When:      values.Timestamp.UTC().Format(time.RFC3339),
+		Depth:     math.Round(float64(values.Depth*10)) / 10,
+		Retracted: values.Retracted,
+	}
+}
+
+var auditOperations = map[string]AuditOperation{
+	database.AuditAdd:     AuditOperationAdd,
+	database.AuditCorrect: AuditOperationCorrect,
+	database.AuditRetract: AuditOperationRetract,
+}
+
+func (r *queryResolver) SnowdepthAuditTrail(ctx context.Context, from *string, to *string, measurement *string, actor *string, limit *int) ([]*SnowdepthAuditEntry, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.AuditQuery{Actor: actor}
+
+	if query.From, err = parseDateTime(from); err != nil {
+		return nil, err
+	}
+
+	if query.To, err = parseDateTime(to); err != nil {
+		return nil, err
+	}
+
+	if measurement != nil {
+		id, err := parseID("measurement", *measurement)
+		if err != nil {
+			return nil, err
+		}
+		query.SnowdepthID = &id
+	}
+
+	if query.Limit, err = parseLimit(limit); err != nil {
+		return nil, err
+	}
+
+	entries, err := db.GetAuditTrail(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlentries := make([]*SnowdepthAuditEntry, 0, len(entries))
+
+	for _, e := range entries {
+		gqlentries = append(gqlentries, &SnowdepthAuditEntry{
+			ID:          strconv.FormatUint(uint64(e.ID), 10),
+			At:          e.CreatedAt.UTC().Format(time.RFC3339),
+			Operation:   auditOperations[e.Operation],
+			Measurement: strconv.FormatUint(uint64(e.SnowdepthID), 10),
+			Actor:       e.Actor,
+			Identity:    e.Identity,
+			ClientIP:    e.ClientIP,
+			UserAgent:   e.UserAgent,
+			Before:      convertAuditedSnowdepthToGQL(e.Before),
+			After:       convertAuditedSnowdepthToGQL(e.After),
+		})
+	}
+
+	return gqlentries, nil
+}
+
+func (r *queryResolver) QuarantinedSnowdepths(ctx context.Context, from *string, to *string, device *string, limit *int) ([]*QuarantinedSnowdepth, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.QuarantineQuery{Device: device}
+
+	if query.From, err = parseDateTime(from); err != nil {
+		return nil, err
+	}
+
+	if query.To, err = parseDateTime(to); err != nil {
+		return nil, err
+	}
+
+	if query.Limit, err = parseLimit(limit); err != nil {
+		return nil, err
+	}
+
+	quarantined, err := db.GetQuarantinedSnowdepths(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlquarantined := make([]*QuarantinedSnowdepth, 0, len(quarantined))
+
+	for _, q := range quarantined {
+		gq := &QuarantinedSnowdepth{
+			ID:         strconv.FormatUint(uint64(q.ID), 10),
+			RejectedAt: q.CreatedAt.UTC().Format(time.RFC3339),
+			From: &Origin{
+				Pos: &WGS84Position{Lat: q.Latitude, Lon: q.Longitude},
+			},
+			When:     q.Timestamp.UTC().Format(time.RFC3339),
+			Depth:    math.Round(float64(q.Depth*10)) / 10,
+			RawDepth: math.Round(float64(q.RawDepth*10)) / 10,
+			Rule:     q.Rule,
+			Reason:   q.Reason,
+		}
+
+		if len(q.Device) > 0 {
+			gq.From.Device = &Device{ID: q.Device}
+		}
+
+		gqlquarantined = append(gqlquarantined, gq)
+	}
+
+	return gqlquarantined, nil
+}
+
+func convertCalibrationToGQL(c *models.DeviceCalibration) *DeviceCalibration {
+	calibration := &DeviceCalibration{
+		ID:             strconv.FormatUint(uint64(c.ID), 10),
+		Device:         &Device{ID: c.Device},
+		MountingHeight: c.MountingHeight,
+		Offset:         c.DepthOffset,
+		Scale:          c.DepthScale,
+		ValidFrom:      c.ValidFrom.UTC().Format(time.RFC3339),
+	}
+
+	if c.ValidTo != nil {
+		validTo := c.ValidTo.UTC().Format(time.RFC3339)
+		calibration.ValidTo = &validTo
+	}
+
+	return calibration
+}
+
+func newCalibration(input DeviceCalibrationInput) (database.Calibration, error) {
+	var err error
+
+	calibration := database.Calibration{
+		Device:         input.Device,
+		MountingHeight: input.MountingHeight,
+		Scale:          1,
+	}
+
+	if input.Offset != nil {
+		calibration.Offset = *input.Offset
+	}
+
+	if input.Scale != nil {
+		calibration.Scale = *input.Scale
+	}
+
+	if calibration.ValidFrom, err = parseDateTime(&input.ValidFrom); err != nil {
+		return calibration, err
+	}
+
+	if input.ValidTo != nil {
+		validTo, err := parseDateTime(input.ValidTo)
+		if err != nil {
+			return calibration, err
+		}
+		calibration.ValidTo = &validTo
+	}
+
+	return calibration, nil
+}
+
+func convertCalibrationChangeToGQL(change database.CalibrationChange) *DeviceCalibrationChange {
+	return &DeviceCalibrationChange{
+		Calibration: convertCalibrationToGQL(change.Calibration),
+		Recomputed:  int(change.Recomputed),
+	}
+}
+
+func (r *queryResolver) DeviceCalibrations(ctx context.Context, device string) ([]*DeviceCalibration, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calibrations, err := db.GetDeviceCalibrations(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlcalibrations := make([]*DeviceCalibration, 0, len(calibrations))
+	for idx := range calibrations {
+		gqlcalibrations = append(gqlcalibrations, convertCalibrationToGQL(&calibrations[idx]))
+	}
+
+	return gqlcalibrations, nil
+}
+
+func (r *mutationResolver) AddDeviceCalibration(ctx context.Context, input DeviceCalibrationInput, recompute *bool) (*DeviceCalibrationChange, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calibration, err := newCalibration(input)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := db.AddDeviceCalibration(ctx, calibration, recompute != nil && *recompute)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCalibrationChangeToGQL(change), nil
+}
+
+func (r *mutationResolver) UpdateDeviceCalibration(ctx context.Context, id string, input DeviceCalibrationInput, recompute *bool) (*DeviceCalibrationChange, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calibrationID, err := parseID("calibration", id)
+	if err != nil {
+		return nil, err
+	}
+
+	calibration, err := newCalibration(input)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := db.UpdateDeviceCalibration(ctx, calibrationID, calibration, recompute != nil && *recompute)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCalibrationChangeToGQL(change), nil
+}
+
+func (r *mutationResolver) DeleteDeviceCalibration(ctx context.Context, id string, recompute *bool) (*DeviceCalibrationChange, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calibrationID, err := parseID("calibration", id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := db.DeleteDeviceCalibration(ctx, calibrationID, recompute != nil && *recompute)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCalibrationChangeToGQL(change), nil
+}
+
+// deviceStatuses maps the statuses in the schema to the statuses in the database, where an
+// undetermined status is empty
+var deviceStatuses = map[DeviceStatus]string{
+	DeviceStatusOnline:  database.DeviceOnline,
+	DeviceStatusStale:   database.DeviceStale,
+	DeviceStatusOffline: database.DeviceOffline,
+	DeviceStatusUnknown: "",
+}
+
+func convertDeviceHealthToGQL(h *models.DeviceHealth) *DeviceHealth {
+	health := &DeviceHealth{
+		Status:          DeviceStatusUnknown,
+		StatusChangedAt: h.StatusChangedAt.UTC().Format(time.RFC3339),
+		LastSeen:        h.LastSeen.UTC().Format(time.RFC3339),
+	}
+
+	for status, value := range deviceStatuses {
+		if value == h.Status {
+			health.Status = status
+		}
+	}
+
+	if h.ExpectedInterval != nil {
+		seconds := int(*h.ExpectedInterval)
+		health.ExpectedInterval = &seconds
+	}
+
+	return health
+}
+
+func newDevice(h *models.DeviceHealth) *Device {
+	return &Device{ID: h.Device, health: h, loaded: true}
+}
+
+func (r *deviceResolver) Health(ctx context.Context, obj *Device) (*DeviceHealth, error) {
+	if !obj.loaded {
+		db, err := database.GetFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		obj.health, err = db.GetDeviceHealth(ctx, obj.ID)
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return nil, err
+		}
+		obj.loaded = true
+	}
+
+	if obj.health == nil {
+		return nil, nil
+	}
+
+	return convertDeviceHealthToGQL(obj.health), nil
+}
+
+func (r *entityResolver) FindDeviceByID(ctx context.Context, id string) (*Device, error) {
+	return &Device{ID: id}, nil
+}
+
+func (r *queryResolver) Devices(ctx context.Context, status *DeviceStatus) ([]*Device, error) {
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *string
+	if status != nil {
+		value := deviceStatuses[*status]
+		filter = &value
+	}
+
+	devices, err := db.GetDevicesHealth(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	gqldevices := make([]*Device, 0, len(devices))
+	for idx := range devices {
+		gqldevices = append(gqldevices, newDevice(&devices[idx]))
+	}
+
+	return gqldevices, nil
+}
+
+func (r *mutationResolver) SetDeviceExpectedInterval(ctx context.Context, device string, seconds *int) (*Device, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var interval *time.Duration
+	if seconds != nil {
+		if *seconds <= 0 {
+			return nil, newBadUserInputError("the expected interval must be a positive number of seconds")
+		}
+		d := time.Duration(*seconds) * time.Second
+		interval = &d
+	}
+
+	health, err := db.SetDeviceExpectedInterval(ctx, device, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDevice(health), nil
+}
+
+func convertAlertRuleToGQL(r *models.AlertRule) *AlertRule {
+	rule := &AlertRule{
+		ID:         strconv.FormatUint(uint64(r.ID), 10),
+		Name:       r.Name,
+		Direction:  AlertDirectionAbove,
+		Threshold:  r.Threshold,
+		Hysteresis: r.Hysteresis,
+		Cooldown:   int(r.Cooldown),
+		Enabled:    r.Enabled,
+	}
+
+	if r.Direction == database.AlertBelow {
+		rule.Direction = AlertDirectionBelow
+	}
+
+	if r.Device != nil {
+		rule.Device = &Device{ID: *r.Device}
+	}
+
+	if r.SiteRadius != nil {
+		rule.Site = &AlertSite{
+			Center: &WGS84Position{Lat: *r.SiteLatitude, Lon: *r.SiteLongitude},
+			Radius: *r.SiteRadius,
+		}
+	}
+
+	if r.WebhookURL != "" {
+		webhookURL := r.WebhookURL
+		rule.WebhookURL = &webhookURL
+	}
+
+	return rule
+}
+
+func newAlertRule(input AlertRuleInput) (database.AlertRuleInput, error) {
+	rule := database.AlertRuleInput{
+		Name:      input.Name,
+		Device:    input.Device,
+		Direction: database.AlertAbove,
+		Threshold: input.Threshold,
+		Enabled:   true,
+	}
+
+	if input.Direction == AlertDirectionBelow {
+		rule.Direction = database.AlertBelow
+	}
+
+	if input.Site != nil {
+		if input.Site.Center == nil {
+			return rule, newBadUserInputError("a site must have a center")
+		}
+		rule.Site = &database.Circle{
+			Latitude:  input.Site.Center.Lat,
+			Longitude: input.Site.Center.Lon,
+			Radius:    input.Site.Radius,
+		}
+	}
+
+	if input.Hysteresis != nil {
+		rule.Hysteresis = *input.Hysteresis
+	}
+
+	if input.Cooldown != nil {
+		rule.Cooldown = time.Duration(*input.Cooldown) * time.Second
+	}
+
+	if input.Enabled != nil {
+		rule.Enabled = *input.Enabled
+	}
+
+	if input.WebhookURL != nil {
+		rule.WebhookURL = *input.WebhookURL
+	}
+
+	return rule, nil
+}
+
+func (r *queryResolver) AlertRules(ctx context.Context) ([]*AlertRule, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := db.GetAlertRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlrules := make([]*AlertRule, 0, len(rules))
+	for idx := range rules {
+		gqlrules = append(gqlrules, convertAlertRuleToGQL(&rules[idx]))
+	}
+
+	return gqlrules, nil
+}
+
+func (r *mutationResolver) AddAlertRule(ctx context.Context, input AlertRuleInput) (*AlertRule, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := newAlertRule(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Alerts.CheckWebhook(rule.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	added, err := db.AddAlertRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAlertRuleToGQL(added), nil
+}
+
+func (r *mutationResolver) UpdateAlertRule(ctx context.Context, id string, input AlertRuleInput) (*AlertRule, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := parseID("alert rule", id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := newAlertRule(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Alerts.CheckWebhook(rule.WebhookURL); err != nil {
+		return nil, err
+	}
+
+	updated, err := db.UpdateAlertRule(ctx, ruleID, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAlertRuleToGQL(updated), nil
+}
+
+func (r *mutationResolver) DeleteAlertRule(ctx context.Context, id string) (*AlertRule, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleID, err := parseID("alert rule", id)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := db.DeleteAlertRule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAlertRuleToGQL(deleted), nil
+}
+
+// deadLetterReasons maps the reasons in the schema to the reasons in the database
+var deadLetterReasons = map[DeadLetterReason]string{
+	DeadLetterReasonMalformed:        database.DeadLetterMalformed,
+	DeadLetterReasonRejected:         database.DeadLetterRejected,
+	DeadLetterReasonRetriesExhausted: database.DeadLetterRetriesExhausted,
+}
+
+// ingestOutcomes maps the outcomes of storing a measurement to the outcomes in the schema
+var ingestOutcomes = map[database.IngestOutcome]IngestOutcome{
+	database.IngestInserted:        IngestOutcomeInserted,
+	database.IngestDuplicate:       IngestOutcomeDuplicate,
+	database.IngestConflictIgnored: IngestOutcomeConflictIgnored,
+	database.IngestOverwritten:     IngestOutcomeOverwritten,
+	database.IngestQuarantined:     IngestOutcomeQuarantined,
+}
+
+func convertDeadLetterToGQL(d *models.DeadLetter) *DeadLetter {
+	letter := &DeadLetter{
+		ID:          strconv.FormatUint(d.ID, 10),
+		ReceivedAt:  d.CreatedAt.UTC().Format(time.RFC3339),
+		RoutingKey:  d.RoutingKey,
+		ContentType: d.ContentType,
+		Payload:     d.Body,
+		Error:       d.Error,
+		Attempts:    d.Attempts,
+	}
+
+	for reason, value := range deadLetterReasons {
+		if value == d.Reason {
+			letter.Reason = reason
+		}
+	}
+
+	if d.ReplayedAt != nil {
+		replayedAt := d.ReplayedAt.UTC().Format(time.RFC3339)
+		letter.ReplayedAt = &replayedAt
+	}
+
+	return letter
+}
+
+func (r *queryResolver) DeadLetters(ctx context.Context, from *string, to *string, reason *DeadLetterReason, replayed *bool, limit *int) ([]*DeadLetter, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.DeadLetterQuery{Replayed: replayed}
+
+	if query.From, err = parseDateTime(from); err != nil {
+		return nil, err
+	}
+
+	if query.To, err = parseDateTime(to); err != nil {
+		return nil, err
+	}
+
+	if query.Limit, err = parseLimit(limit); err != nil {
+		return nil, err
+	}
+
+	if reason != nil {
+		value := deadLetterReasons[*reason]
+		query.Reason = &value
+	}
+
+	letters, err := db.GetDeadLetters(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlletters := make([]*DeadLetter, 0, len(letters))
+	for idx := range letters {
+		gqlletters = append(gqlletters, convertDeadLetterToGQL(&letters[idx]))
+	}
+
+	return gqlletters, nil
+}
+
+func (r *mutationResolver) ReplayDeadLetter(ctx context.Context, id string) (*DeadLetterReplay, error) {
+	if err := requireRole(ctx, auth.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	letterID, err := parseID("dead letter", id)
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := database.ReplayDeadLetter(ctx, db, uint64(letterID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DeadLetterReplay{DeadLetter: convertDeadLetterToGQL(replay.DeadLetter)}
+
+	if replay.Err != nil {
+		message := replay.Err.Error()
+		result.Error = &message
+	}
+
+	if outcome, ok := ingestOutcomes[replay.Outcome]; ok && replay.Err == nil {
+		result.Outcome = &outcome
+	}
+
+	if replay.Measurement != nil {
+		result.Snowdepth = convertDatabaseRecordToGQL(replay.Measurement)
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) Device() DeviceResolver     { return &deviceResolver{r} }
 func (r *Resolver) Entity() EntityResolver     { return &entityResolver{r} }
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 func (r *Resolver) Query() QueryResolver       { return &queryResolver{r} }
 
+type deviceResolver struct{ *Resolver }
 type entityResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
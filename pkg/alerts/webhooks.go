@@ -0,0 +1,167 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// SignatureHeader carries the signature of a webhook request, given as t=<unix time>,v1=<hex>
+// where the second value is the HMAC-SHA256 of "<unix time>.<body>" with the webhook secret
+const SignatureHeader = "X-Snowdepth-Signature"
+
+// Config controls how alerts are delivered to webhooks
+type Config struct {
+	WebhookSecret   []byte
+	WebhookTimeout  time.Duration
+	WebhookAttempts int
+}
+
+// LoadConfig reads the webhook configuration from SNOWDEPTH_ALERT_WEBHOOK_SECRET,
+// SNOWDEPTH_ALERT_WEBHOOK_TIMEOUT and SNOWDEPTH_ALERT_WEBHOOK_ATTEMPTS
+func LoadConfig() (Config, error) {
+	cfg := Config{
+		WebhookSecret:   []byte(os.Getenv("SNOWDEPTH_ALERT_WEBHOOK_SECRET")),
+		WebhookTimeout:  10 * time.Second,
+		WebhookAttempts: 3,
+	}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_ALERT_WEBHOOK_TIMEOUT"); ok {
+		if cfg.WebhookTimeout, err = time.ParseDuration(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_ALERT_WEBHOOK_TIMEOUT: %w", value, err)
+		}
+	}
+
+	if cfg.WebhookTimeout <= 0 {
+		return cfg, fmt.Errorf("SNOWDEPTH_ALERT_WEBHOOK_TIMEOUT must be positive")
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_ALERT_WEBHOOK_ATTEMPTS"); ok {
+		if cfg.WebhookAttempts, err = strconv.Atoi(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_ALERT_WEBHOOK_ATTEMPTS: %w", value, err)
+		}
+	}
+
+	if cfg.WebhookAttempts < 1 {
+		return cfg, fmt.Errorf("SNOWDEPTH_ALERT_WEBHOOK_ATTEMPTS must be at least 1")
+	}
+
+	return cfg, nil
+}
+
+// CheckRules returns an error if any of the rules has a webhook but there is no secret to sign
+// the webhook requests with
+func CheckRules(cfg Config, rules []models.AlertRule) error {
+	for _, rule := range rules {
+		if err := cfg.CheckWebhook(rule.WebhookURL); err != nil {
+			return fmt.Errorf("alert rule %d has a webhook: %w", rule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckWebhook returns a validation error if a webhook is given but there is no secret to
+// sign the webhook requests with
+func (cfg Config) CheckWebhook(url string) error {
+	if url != "" && len(cfg.WebhookSecret) == 0 {
+		return fmt.Errorf("%w: webhooks can not be used since SNOWDEPTH_ALERT_WEBHOOK_SECRET is not set", database.ErrValidation)
+	}
+
+	return nil
+}
+
+// Webhooks posts the alerts in the outbox to the webhooks of their rules
+type Webhooks struct {
+	cfg    Config
+	client *http.Client
+	logger zerolog.Logger
+}
+
+// NewWebhooks creates a Webhooks that signs its requests with the configured secret
+func NewWebhooks(cfg Config, logger zerolog.Logger) *Webhooks {
+	return &Webhooks{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.WebhookTimeout},
+		logger: logger.With().Str("component", "alerts").Logger(),
+	}
+}
+
+// Deliver posts an alert from the outbox to its webhook. An error is returned if the request
+// fails, so that the alert is kept in the outbox and retried the next time the outbox is
+// relayed. The alert is given up on, and nil is returned, once it has been attempted as many
+// times as configured.
+func (w *Webhooks) Deliver(ctx context.Context, msg database.OutboxMessage) error {
+	logger := w.logger.With().Uint64("id", msg.ID).Int("attempt", msg.Attempts+1).Logger()
+
+	err := w.post(ctx, msg)
+	if err == nil {
+		logger.Debug().Msg("delivered alert to webhook")
+		return nil
+	}
+
+	if msg.Attempts+1 >= w.cfg.WebhookAttempts {
+		logger.Error().Err(err).Msg("giving up on delivering alert to webhook")
+		return nil
+	}
+
+	return err
+}
+
+func (w *Webhooks) post(ctx context.Context, msg database.OutboxMessage) error {
+	if len(w.cfg.WebhookSecret) == 0 {
+		return errors.New("not calling webhook since SNOWDEPTH_ALERT_WEBHOOK_SECRET is not set")
+	}
+
+	body, err := msg.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", msg.ContentType())
+	req.Header.Set(SignatureHeader, Sign(w.cfg.WebhookSecret, time.Now(), body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign computes the value of the signature header for a webhook request body. Receivers
+// should compute the same value and reject requests with old timestamps to prevent replays.
+func Sign(secret []byte, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,89 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+func TestCheckWebhook(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		url    string
+		fails  bool
+	}{
+		{"no webhook without secret", "", "", false},
+		{"no webhook with secret", "secret", "", false},
+		{"webhook with secret", "secret", "https://example.com/hooks/snow", false},
+		{"webhook without secret", "", "https://example.com/hooks/snow", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{WebhookSecret: []byte(tc.secret)}
+
+			err := cfg.CheckWebhook(tc.url)
+			if tc.fails && !errors.Is(err, database.ErrValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			} else if !tc.fails && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+
+			rules := []models.AlertRule{{Name: "a"}, {Name: "b", WebhookURL: tc.url}}
+			if err := CheckRules(cfg, rules); tc.fails != (err != nil) {
+				t.Errorf("expected CheckRules to fail to be %t, got %v", tc.fails, err)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	status := http.StatusOK
+	signature := ""
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := Config{WebhookSecret: []byte("secret"), WebhookTimeout: time.Second, WebhookAttempts: 3}
+	webhooks := NewWebhooks(cfg, zerolog.Nop())
+
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		fails    bool
+	}{
+		{"delivered", http.StatusOK, 0, false},
+		{"failed", http.StatusInternalServerError, 0, true},
+		{"failed again", http.StatusInternalServerError, 1, true},
+		{"given up on after the last attempt", http.StatusInternalServerError, 2, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status, signature = tc.status, ""
+
+			msg := database.OutboxMessage{ID: 1, Webhook: server.URL, Attempts: tc.attempts}
+
+			err := webhooks.Deliver(context.Background(), msg)
+			if tc.fails != (err != nil) {
+				t.Errorf("expected failure to be %t, got %v", tc.fails, err)
+			}
+			if signature == "" {
+				t.Errorf("expected the request to be signed")
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// RoleAdmin is granted to clients that may read the audit trail and administer the service
+const RoleAdmin = "admin"
+
+// Identity is the authenticated client that made a request
+type Identity struct {
+	Name  string
+	Roles []string
+}
+
+// HasRole returns true if the identity has been granted the role
+func (i Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of ctx that carries the identity
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity that made the request, if it was authenticated
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+type apiKey struct {
+	key      []byte
+	identity Identity
+}
+
+// Keys contains the API keys that clients can authenticate with
+type Keys struct {
+	keys []apiKey
+}
+
+// LoadKeys reads the named API keys from SNOWDEPTH_API_KEYS, which is a comma separated list
+// of name:key entries, optionally followed by a :role|role list. A key in DIWISE_API_KEY is
+// accepted as well, under the name "diwise" and without any roles.
+func LoadKeys() (Keys, error) {
+	keys := Keys{}
+
+	if key := os.Getenv("DIWISE_API_KEY"); key != "" {
+		keys.add("diwise", key, nil)
+	}
+
+	for _, entry := range strings.Split(os.Getenv("SNOWDEPTH_API_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return Keys{}, fmt.Errorf("invalid entry in SNOWDEPTH_API_KEYS, expected name:key[:roles]")
+		}
+
+		var roles []string
+		if len(parts) == 3 && parts[2] != "" {
+			roles = strings.Split(parts[2], "|")
+		}
+
+		keys.add(parts[0], parts[1], roles)
+	}
+
+	return keys, nil
+}
+
+func (k *Keys) add(name, key string, roles []string) {
+	k.keys = append(k.keys, apiKey{key: []byte(key), identity: Identity{Name: name, Roles: roles}})
+}
+
+// Empty returns true if no keys have been configured
+func (k Keys) Empty() bool {
+	return len(k.keys) == 0
+}
+
+// Authenticate looks up the identity of the key in the x-api-key header, or the bearer token
+// in the Authorization header. The second return value is false if the request did not carry
+// any credentials, and an error is returned if the credentials are unknown.
+func (k Keys) Authenticate(r *http.Request) (Identity, bool, error) {
+	credential := r.Header.Get("x-api-key")
+
+	if credential == "" {
+		const prefix = "bearer "
+		header := r.Header.Get("Authorization")
+		if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+			credential = strings.TrimSpace(header[len(prefix):])
+		}
+	}
+
+	if credential == "" {
+		return Identity{}, false, nil
+	}
+
+	for _, key := range k.keys {
+		if subtle.ConstantTimeCompare(key.key, []byte(credential)) == 1 {
+			return key.identity, true, nil
+		}
+	}
+
+	return Identity{}, true, fmt.Errorf("unknown api key")
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLoadKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		diwiseKey string
+		apiKeys   string
+		expected  []apiKey
+		fails     bool
+	}{
+		{name: "none"},
+		{
+			name:      "diwise key",
+			diwiseKey: "secret",
+			expected:  []apiKey{{key: []byte("secret"), identity: Identity{Name: "diwise"}}},
+		},
+		{
+			name:    "named keys with and without roles",
+			apiKeys: "ops:k1:admin|reader, app:k2,viewer:k3:",
+			expected: []apiKey{
+				{key: []byte("k1"), identity: Identity{Name: "ops", Roles: []string{"admin", "reader"}}},
+				{key: []byte("k2"), identity: Identity{Name: "app"}},
+				{key: []byte("k3"), identity: Identity{Name: "viewer"}},
+			},
+		},
+		{
+			name:      "both",
+			diwiseKey: "secret",
+			apiKeys:   "ops:k1:admin,",
+			expected: []apiKey{
+				{key: []byte("secret"), identity: Identity{Name: "diwise"}},
+				{key: []byte("k1"), identity: Identity{Name: "ops", Roles: []string{"admin"}}},
+			},
+		},
+		{name: "missing key", apiKeys: "ops", fails: true},
+		{name: "empty key", apiKeys: "ops:", fails: true},
+		{name: "empty name", apiKeys: ":k1", fails: true},
+		{name: "too many parts", apiKeys: "ops:k1:admin:extra", fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("DIWISE_API_KEY", tc.diwiseKey)
+			t.Setenv("SNOWDEPTH_API_KEYS", tc.apiKeys)
+
+			keys, err := LoadKeys()
+
+			if tc.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", keys)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if keys.Empty() != (len(tc.expected) == 0) {
+				t.Errorf("expected Empty to be %t", len(tc.expected) == 0)
+			}
+			if !reflect.DeepEqual(keys.keys, tc.expected) {
+				t.Errorf("expected keys %+v, got %+v", tc.expected, keys.keys)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Setenv("DIWISE_API_KEY", "")
+	t.Setenv("SNOWDEPTH_API_KEYS", "ops:k1:admin,app:k2")
+
+	keys, err := LoadKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		expectedName  string
+		expectedFound bool
+		expectedAdmin bool
+		fails         bool
+	}{
+		{name: "no credentials"},
+		{name: "api key", headers: map[string]string{"x-api-key": "k1"}, expectedName: "ops", expectedFound: true, expectedAdmin: true},
+		{name: "bearer token", headers: map[string]string{"Authorization": "Bearer k2"}, expectedName: "app", expectedFound: true},
+		{name: "bearer is case insensitive", headers: map[string]string{"Authorization": "bearer  k1 "}, expectedName: "ops", expectedFound: true, expectedAdmin: true},
+		{name: "api key is preferred", headers: map[string]string{"x-api-key": "k2", "Authorization": "Bearer k1"}, expectedName: "app", expectedFound: true},
+		{name: "other scheme is ignored", headers: map[string]string{"Authorization": "Basic k1"}},
+		{name: "unknown api key", headers: map[string]string{"x-api-key": "k3"}, expectedFound: true, fails: true},
+		{name: "unknown bearer token", headers: map[string]string{"Authorization": "Bearer k"}, expectedFound: true, fails: true},
+		{name: "prefix of a key", headers: map[string]string{"x-api-key": "k"}, expectedFound: true, fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/graphql", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			identity, found, err := keys.Authenticate(r)
+
+			if tc.fails != (err != nil) {
+				t.Errorf("expected failure to be %t, got %v", tc.fails, err)
+			}
+			if found != tc.expectedFound {
+				t.Errorf("expected found to be %t", tc.expectedFound)
+			}
+			if identity.Name != tc.expectedName {
+				t.Errorf("expected identity %q, got %q", tc.expectedName, identity.Name)
+			}
+			if identity.HasRole(RoleAdmin) != tc.expectedAdmin {
+				t.Errorf("expected admin role to be %t", tc.expectedAdmin)
+			}
+		})
+	}
+}
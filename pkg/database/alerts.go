@@ -0,0 +1,406 @@
+package database
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// Directions in which the depth can cross the threshold of an alert rule
+const (
+	AlertAbove = "above"
+	AlertBelow = "below"
+)
+
+// AlertRuleInput contains the values of a new or changed alert rule
+type AlertRuleInput struct {
+	Name       string
+	Device     *string
+	Site       *Circle
+	Direction  string
+	Threshold  float64
+	Hysteresis float64
+	Cooldown   time.Duration
+	Enabled    bool
+	WebhookURL string
+}
+
+func (r AlertRuleInput) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return newError(ErrValidation, "an alert rule must have a name")
+	}
+
+	if r.Device != nil && strings.TrimSpace(*r.Device) == "" {
+		return newError(ErrValidation, "the device of an alert rule must not be empty")
+	}
+
+	if r.Site != nil {
+		if err := (Area{Circle: r.Site}).Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Direction != AlertAbove && r.Direction != AlertBelow {
+		return newError(ErrValidation, "the direction of an alert rule must be %q or %q", AlertAbove, AlertBelow)
+	}
+
+	if math.IsNaN(r.Threshold) || math.IsInf(r.Threshold, 0) {
+		return newError(ErrValidation, "the threshold of an alert rule must be a number")
+	}
+
+	if r.Hysteresis < 0 || math.IsNaN(r.Hysteresis) || math.IsInf(r.Hysteresis, 0) {
+		return newError(ErrValidation, "the hysteresis of an alert rule must not be negative")
+	}
+
+	if r.Cooldown < 0 {
+		return newError(ErrValidation, "the cooldown of an alert rule must not be negative")
+	}
+
+	if r.WebhookURL != "" {
+		u, err := url.Parse(r.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return newError(ErrValidation, "the webhook of an alert rule must be an absolute http or https url")
+		}
+	}
+
+	return nil
+}
+
+func (r AlertRuleInput) apply(rule *models.AlertRule) {
+	rule.Name = strings.TrimSpace(r.Name)
+	rule.Device = r.Device
+	rule.SiteLatitude = nil
+	rule.SiteLongitude = nil
+	rule.SiteRadius = nil
+
+	if r.Site != nil {
+		site := *r.Site
+		rule.SiteLatitude = &site.Latitude
+		rule.SiteLongitude = &site.Longitude
+		rule.SiteRadius = &site.Radius
+	}
+
+	rule.Direction = r.Direction
+	rule.Threshold = r.Threshold
+	rule.Hysteresis = r.Hysteresis
+	rule.Cooldown = int64(r.Cooldown / time.Second)
+	rule.Enabled = r.Enabled
+	rule.WebhookURL = r.WebhookURL
+}
+
+// Alert is raised when a measurement makes the depth cross the threshold of a rule
+type Alert struct {
+	Rule        models.AlertRule
+	Measurement models.Snowdepth
+	TriggeredAt time.Time
+}
+
+// alertKey identifies the state of a rule for a device
+type alertKey struct {
+	rule   uint
+	device string
+}
+
+// appliesTo reports whether the rule applies to a measurement
+func appliesTo(rule *models.AlertRule, m *models.Snowdepth) bool {
+	if !rule.Enabled {
+		return false
+	}
+
+	if rule.Device != nil && *rule.Device != m.Device {
+		return false
+	}
+
+	if rule.SiteRadius != nil {
+		site := Area{Circle: &Circle{Latitude: *rule.SiteLatitude, Longitude: *rule.SiteLongitude, Radius: *rule.SiteRadius}}
+		if !site.Contains(m.Latitude, m.Longitude) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evaluateAlert updates the state of a rule with a new measurement and reports whether an
+// alert should be sent. The rule is triggered when the depth reaches the threshold, and is
+// re-armed when the depth has moved back past the threshold by more than the hysteresis.
+// Measurements that are older than the last evaluated one are ignored, while the last one is
+// evaluated again when it has been overwritten or corrected.
+func evaluateAlert(rule *models.AlertRule, state *models.AlertState, m *models.Snowdepth, now time.Time) bool {
+	if state.LastTimestamp != nil && m.Timestamp.Before(*state.LastTimestamp) {
+		return false
+	}
+
+	timestamp := m.Timestamp
+	state.LastTimestamp = &timestamp
+
+	depth := float64(m.Depth)
+	crossed := depth >= rule.Threshold
+	rearmed := depth < rule.Threshold-rule.Hysteresis
+
+	if rule.Direction == AlertBelow {
+		crossed = depth <= rule.Threshold
+		rearmed = depth > rule.Threshold+rule.Hysteresis
+	}
+
+	if state.Active {
+		if rearmed {
+			state.Active = false
+		}
+		return false
+	}
+
+	if !crossed {
+		return false
+	}
+
+	state.Active = true
+
+	cooldown := time.Duration(rule.Cooldown) * time.Second
+	if state.LastAlertAt != nil && now.Sub(*state.LastAlertAt) < cooldown {
+		return false
+	}
+
+	alertAt := now
+	state.LastAlertAt = &alertAt
+
+	return true
+}
+
+// evaluateAlerts applies the rules to the measurements in chronological order, using and
+// updating the states of the rules, and returns the alerts that were raised. Manually added
+// measurements do not belong to a series, so each of them is evaluated with a fresh state
+// that is not stored, and raises an alert whenever it reaches the threshold.
+func evaluateAlerts(rules []models.AlertRule, states map[alertKey]*models.AlertState, measurements []*models.Snowdepth, now time.Time) []Alert {
+	ordered := make([]*models.Snowdepth, len(measurements))
+	copy(ordered, measurements)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+	})
+
+	alerts := []Alert{}
+
+	for _, m := range ordered {
+		for idx := range rules {
+			rule := &rules[idx]
+			if !appliesTo(rule, m) {
+				continue
+			}
+
+			state := &models.AlertState{RuleID: rule.ID}
+
+			if m.Device != "" {
+				key := alertKey{rule: rule.ID, device: m.Device}
+				if stored, ok := states[key]; ok {
+					state = stored
+				} else {
+					state.Device = m.Device
+					states[key] = state
+				}
+			}
+
+			if evaluateAlert(rule, state, m, now) {
+				alerts = append(alerts, Alert{Rule: *rule, Measurement: *m, TriggeredAt: now})
+			}
+		}
+	}
+
+	return alerts
+}
+
+// GetAlertRules returns all alert rules ordered by id
+func (db *myDB) GetAlertRules(ctx context.Context) ([]models.AlertRule, error) {
+	rules := []models.AlertRule{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.Order("id").Find(&rules).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// AddAlertRule adds an alert rule
+func (db *myDB) AddAlertRule(ctx context.Context, input AlertRuleInput) (*models.AlertRule, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	rule := &models.AlertRule{}
+	input.apply(rule)
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		return tx.Create(rule).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// UpdateAlertRule changes an alert rule. The state of the rule is reset, so that it is
+// evaluated from scratch with the next measurement.
+func (db *myDB) UpdateAlertRule(ctx context.Context, id uint, input AlertRuleInput) (*models.AlertRule, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	rule := &models.AlertRule{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(rule, id).Error; err != nil {
+			return err
+		}
+
+		input.apply(rule)
+
+		if err := tx.Save(rule).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("rule_id = ?", id).Delete(&models.AlertState{}).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteAlertRule removes an alert rule together with its state
+func (db *myDB) DeleteAlertRule(ctx context.Context, id uint) (*models.AlertRule, error) {
+	rule := &models.AlertRule{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := tx.First(rule, id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(rule).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// raiseAlerts applies the enabled alert rules to measurements that are being stored, and adds
+// the alerts that they raise to the outbox, as part of the same transaction. The states of the
+// affected rules are locked until the end of the transaction, so that concurrent ingests can
+// not raise the same alert twice, and alerts are never sent for writes that are rolled back.
+func raiseAlerts(tx *gorm.DB, measurements ...*models.Snowdepth) error {
+	if len(measurements) == 0 {
+		return nil
+	}
+
+	rules := []models.AlertRule{}
+	if err := tx.Where("enabled").Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+
+	keys := []alertKey{}
+	seen := map[alertKey]bool{}
+
+	for _, m := range measurements {
+		if m.Device == "" {
+			continue
+		}
+
+		for idx := range rules {
+			key := alertKey{rule: rules[idx].ID, device: m.Device}
+			if appliesTo(&rules[idx], m) && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	states := map[alertKey]*models.AlertState{}
+
+	if len(keys) > 0 {
+		var err error
+		if states, err = lockAlertStates(tx, keys); err != nil {
+			return err
+		}
+	}
+
+	alerts := evaluateAlerts(rules, states, measurements, time.Now().UTC())
+
+	for _, key := range keys {
+		if err := tx.Save(states[key]).Error; err != nil {
+			return err
+		}
+	}
+
+	entries, err := alertEntries(alerts)
+	if err != nil {
+		return err
+	}
+
+	for idx := range entries {
+		if err := tx.Create(&entries[idx]).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockAlertStates creates the missing states and locks all of them. The keys are sorted by
+// rule and device first, so that concurrent evaluations always take the row locks in the
+// same order and can not deadlock.
+func lockAlertStates(tx *gorm.DB, keys []alertKey) (map[alertKey]*models.AlertState, error) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rule != keys[j].rule {
+			return keys[i].rule < keys[j].rule
+		}
+		return keys[i].device < keys[j].device
+	})
+
+	values := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys)*2)
+
+	for _, key := range keys {
+		values = append(values, "(?, ?)")
+		args = append(args, key.rule, key.device)
+	}
+
+	err := tx.Exec(
+		"INSERT INTO alert_states (rule_id, device) VALUES "+strings.Join(values, ", ")+" ON CONFLICT DO NOTHING",
+		args...,
+	).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []models.AlertState{}
+	err = tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("(rule_id, device) IN ("+strings.Join(values, ", ")+")", args...).
+		Order("rule_id, device").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[alertKey]*models.AlertState{}
+	for idx := range rows {
+		states[alertKey{rule: rows[idx].RuleID, device: rows[idx].Device}] = &rows[idx]
+	}
+
+	return states, nil
+}
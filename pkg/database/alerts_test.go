@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/events"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+type alertStep struct {
+	offset   time.Duration
+	depth    float32
+	now      time.Duration
+	expected bool
+}
+
+func TestEvaluateAlert(t *testing.T) {
+	above := models.AlertRule{ID: 1, Direction: AlertAbove, Threshold: 20, Hysteresis: 5, Enabled: true}
+	below := models.AlertRule{ID: 2, Direction: AlertBelow, Threshold: 10, Hysteresis: 2, Enabled: true}
+
+	withCooldown := above
+	withCooldown.Cooldown = int64(time.Hour / time.Second)
+
+	tests := []struct {
+		name  string
+		rule  models.AlertRule
+		steps []alertStep
+	}{
+		{
+			name: "above triggers once until rearmed",
+			rule: above,
+			steps: []alertStep{
+				{0, 10, 0, false},
+				{time.Minute, 20, time.Minute, true},
+				{2 * time.Minute, 25, 2 * time.Minute, false},
+				{3 * time.Minute, 16, 3 * time.Minute, false},
+				{4 * time.Minute, 21, 4 * time.Minute, false},
+				{5 * time.Minute, 14, 5 * time.Minute, false},
+				{6 * time.Minute, 22, 6 * time.Minute, true},
+			},
+		},
+		{
+			name: "below triggers once until rearmed",
+			rule: below,
+			steps: []alertStep{
+				{0, 15, 0, false},
+				{time.Minute, 10, time.Minute, true},
+				{2 * time.Minute, 11, 2 * time.Minute, false},
+				{3 * time.Minute, 9, 3 * time.Minute, false},
+				{4 * time.Minute, 13, 4 * time.Minute, false},
+				{5 * time.Minute, 8, 5 * time.Minute, true},
+			},
+		},
+		{
+			name: "cooldown suppresses alerts after rearming",
+			rule: withCooldown,
+			steps: []alertStep{
+				{0, 30, 0, true},
+				{time.Minute, 10, time.Minute, false},
+				{2 * time.Minute, 30, 2 * time.Minute, false},
+				{3 * time.Minute, 10, 3 * time.Minute, false},
+				{4 * time.Minute, 30, 61 * time.Minute, true},
+			},
+		},
+		{
+			name: "older measurements are ignored while the last one is evaluated again",
+			rule: above,
+			steps: []alertStep{
+				{time.Hour, 10, 0, false},
+				{0, 30, time.Minute, false},
+				{time.Hour, 30, 2 * time.Minute, true},
+				{2 * time.Hour, 30, 3 * time.Minute, false},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &models.AlertState{RuleID: tc.rule.ID, Device: "a"}
+
+			for idx, step := range tc.steps {
+				m := &models.Snowdepth{Device: "a", Depth: step.depth, Timestamp: testStart.Add(step.offset)}
+
+				if alert := evaluateAlert(&tc.rule, state, m, testStart.Add(step.now)); alert != step.expected {
+					t.Errorf("step %d (depth %v): expected alert to be %t", idx, step.depth, step.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	device := "a"
+
+	rules := []models.AlertRule{
+		{ID: 1, Name: "all", Direction: AlertAbove, Threshold: 20, Enabled: true},
+		{ID: 2, Name: "device", Device: &device, Direction: AlertAbove, Threshold: 50, Enabled: true},
+	}
+
+	measurement := func(device string, offset time.Duration, depth float32) *models.Snowdepth {
+		return &models.Snowdepth{Device: device, Depth: depth, Timestamp: testStart.Add(offset), Latitude: 62.39, Longitude: 17.30}
+	}
+
+	tests := []struct {
+		name         string
+		measurements []*models.Snowdepth
+		expected     []string
+	}{
+		{
+			name:         "devices have separate states",
+			measurements: []*models.Snowdepth{measurement("a", 0, 30), measurement("b", 0, 30), measurement("a", time.Minute, 30)},
+			expected:     []string{"all:a", "all:b"},
+		},
+		{
+			name:         "measurements are evaluated in chronological order",
+			measurements: []*models.Snowdepth{measurement("a", time.Minute, 30), measurement("a", 0, 10)},
+			expected:     []string{"all:a"},
+		},
+		{
+			name:         "device rules only apply to their device",
+			measurements: []*models.Snowdepth{measurement("b", 0, 60), measurement("a", time.Minute, 60)},
+			expected:     []string{"all:b", "all:a", "device:a"},
+		},
+		{
+			name:         "manual measurements do not share a state",
+			measurements: []*models.Snowdepth{measurement("", 0, 30), measurement("", time.Minute, 30)},
+			expected:     []string{"all:", "all:"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			states := map[alertKey]*models.AlertState{}
+
+			alerts := evaluateAlerts(rules, states, tc.measurements, testStart)
+
+			raised := []string{}
+			for _, a := range alerts {
+				raised = append(raised, a.Rule.Name+":"+a.Measurement.Device)
+			}
+
+			if len(raised) != len(tc.expected) {
+				t.Fatalf("expected alerts %v, got %v", tc.expected, raised)
+			}
+			for idx := range raised {
+				if raised[idx] != tc.expected[idx] {
+					t.Errorf("expected alerts %v, got %v", tc.expected, raised)
+					break
+				}
+			}
+
+			if _, ok := states[alertKey{rule: 1, device: ""}]; ok {
+				t.Errorf("expected no state to be stored for manual measurements")
+			}
+		})
+	}
+}
+
+func TestInMemoryAlertsAreAddedToTheOutbox(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	_, err := db.AddAlertRule(ctx, AlertRuleInput{
+		Name: "deep", Direction: AlertAbove, Threshold: 20, Enabled: true, WebhookURL: "https://example.com/hook",
+	})
+	if err != nil {
+		t.Fatalf("failed to add alert rule: %s", err)
+	}
+
+	addMeasurement(t, db, "a", 0, 10)
+	addMeasurement(t, db, "a", time.Minute, 30)
+
+	relayed := []OutboxMessage{}
+	result, err := db.RelayOutbox(ctx, 10, func(_ context.Context, msg OutboxMessage) error {
+		relayed = append(relayed, msg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to relay outbox: %s", err)
+	}
+
+	expected := []struct{ topic, webhook string }{
+		{events.SnowdepthStoredTopic, ""},
+		{events.SnowdepthStoredTopic, ""},
+		{events.SnowdepthAlertTopic, ""},
+		{events.SnowdepthAlertTopic, "https://example.com/hook"},
+	}
+
+	if result.Published != len(expected) || len(relayed) != len(expected) {
+		t.Fatalf("expected %d messages to be relayed, got %d", len(expected), len(relayed))
+	}
+
+	for idx, e := range expected {
+		if relayed[idx].TopicName() != e.topic || relayed[idx].Webhook != e.webhook {
+			t.Errorf("message %d: expected topic %q and webhook %q, got %q and %q", idx, e.topic, e.webhook, relayed[idx].TopicName(), relayed[idx].Webhook)
+		}
+	}
+}
+
+func TestInMemoryCorrectionsArePublishedAndEvaluated(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	_, err := db.AddAlertRule(ctx, AlertRuleInput{Name: "deep", Direction: AlertAbove, Threshold: 20, Enabled: true})
+	if err != nil {
+		t.Fatalf("failed to add alert rule: %s", err)
+	}
+
+	stored := addMeasurement(t, db, "a", 0, 10)
+
+	depth := 25.0
+	_, err = db.CorrectSnowdepthMeasurement(ctx, stored.ID, SnowdepthCorrection{
+		MeasurementChange: MeasurementChange{ChangedBy: "test", Reason: "misread"},
+		Depth:             &depth,
+	})
+	if err != nil {
+		t.Fatalf("failed to correct measurement: %s", err)
+	}
+
+	topics := []string{}
+	_, err = db.RelayOutbox(ctx, 10, func(_ context.Context, msg OutboxMessage) error {
+		topics = append(topics, msg.TopicName())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to relay outbox: %s", err)
+	}
+
+	expected := []string{events.SnowdepthStoredTopic, events.SnowdepthStoredTopic, events.SnowdepthAlertTopic}
+	if len(topics) != len(expected) {
+		t.Fatalf("expected messages on %v, got %v", expected, topics)
+	}
+	for idx := range expected {
+		if topics[idx] != expected[idx] {
+			t.Errorf("expected messages on %v, got %v", expected, topics)
+			break
+		}
+	}
+}
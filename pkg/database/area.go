@@ -0,0 +1,317 @@
+package database
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+const (
+	earthRadiusMeters = 6371000.0
+	metersPerDegree   = earthRadiusMeters * math.Pi / 180.0
+)
+
+// positionExpr must match the expression of the idx_snowdepths_position index for the
+// index to be used
+const positionExpr = "point(longitude::float8, latitude::float8)"
+
+// BoundingBox is an area limited by two latitudes and two longitudes (WGS84)
+type BoundingBox struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// Circle is an area within a radius (in meters) from a position (WGS84)
+type Circle struct {
+	Latitude  float64
+	Longitude float64
+	Radius    float64
+}
+
+// Position is a WGS84 position
+type Position struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Polygon is an area enclosed by a ring of positions (WGS84). The ring may be closed by
+// repeating the first position, as in GeoJSON, but does not have to be. Edges are straight
+// lines in longitude and latitude, and a polygon can not cross the antimeridian.
+type Polygon struct {
+	Ring []Position
+}
+
+// Area restricts a query to measurements within either a bounding box, a circle or a polygon
+type Area struct {
+	Box     *BoundingBox
+	Circle  *Circle
+	Polygon *Polygon
+}
+
+// Validate checks that exactly one kind of area has been specified and that it is well formed
+func (a Area) Validate() error {
+	kinds := 0
+	for _, isSet := range []bool{a.Box != nil, a.Circle != nil, a.Polygon != nil} {
+		if isSet {
+			kinds++
+		}
+	}
+
+	if kinds != 1 {
+		return newError(ErrValidation, "an area must be either a bounding box, a circle or a polygon")
+	}
+
+	if a.Polygon != nil {
+		return a.Polygon.validate()
+	}
+
+	if a.Box != nil {
+		b := a.Box
+		if !isValidPosition(b.South, b.West) || !isValidPosition(b.North, b.East) {
+			return newError(ErrValidation, "bounding box corners must be valid WGS84 positions")
+		}
+		if b.South > b.North || b.West > b.East {
+			return newError(ErrValidation, "the south west corner of a bounding box must be south west of the north east corner")
+		}
+		return nil
+	}
+
+	c := a.Circle
+	if !isValidPosition(c.Latitude, c.Longitude) {
+		return newError(ErrValidation, "the center of a circle must be a valid WGS84 position")
+	}
+	if c.Radius <= 0 {
+		return newError(ErrValidation, "the radius of a circle must be positive")
+	}
+
+	return nil
+}
+
+// Contains reports whether a position is within the area
+func (a Area) Contains(latitude, longitude float64) bool {
+	if a.Box != nil {
+		return a.Box.contains(latitude, longitude)
+	}
+
+	if a.Circle != nil {
+		return distance(a.Circle.Latitude, a.Circle.Longitude, latitude, longitude) <= a.Circle.Radius
+	}
+
+	if a.Polygon != nil {
+		return a.Polygon.contains(latitude, longitude)
+	}
+
+	return true
+}
+
+func (b BoundingBox) contains(latitude, longitude float64) bool {
+	return latitude >= b.South && latitude <= b.North && longitude >= b.West && longitude <= b.East
+}
+
+func (p Polygon) validate() error {
+	for _, pos := range p.Ring {
+		if !isValidPosition(pos.Latitude, pos.Longitude) {
+			return newError(ErrValidation, "the corners of a polygon must be valid WGS84 positions")
+		}
+	}
+
+	if len(p.vertices()) < 3 {
+		return newError(ErrValidation, "a polygon must have at least three corners")
+	}
+
+	return nil
+}
+
+// vertices returns the corners of the polygon, without the position that closes the ring
+func (p Polygon) vertices() []Position {
+	ring := p.Ring
+	if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+		ring = ring[:n-1]
+	}
+	return ring
+}
+
+// boundingBox returns the smallest box that encloses the polygon
+func (p Polygon) boundingBox() BoundingBox {
+	box := BoundingBox{South: 90.0, West: 180.0, North: -90.0, East: -180.0}
+
+	for _, pos := range p.Ring {
+		box.South = math.Min(box.South, pos.Latitude)
+		box.North = math.Max(box.North, pos.Latitude)
+		box.West = math.Min(box.West, pos.Longitude)
+		box.East = math.Max(box.East, pos.Longitude)
+	}
+
+	return box
+}
+
+// geometryEpsilon is the tolerance, in degrees, that Postgres uses when it compares
+// geometric values
+const geometryEpsilon = 1.0e-6
+
+// contains reports whether a position is inside the polygon. Positions on the outline are
+// inside, as they are for the Postgres <@ operator, and so are positions within
+// geometryEpsilon of it. Other positions are inside if a ray going east from the position
+// crosses an odd number of edges. Positions outside the bounding box are rejected first.
+func (p Polygon) contains(latitude, longitude float64) bool {
+	box := p.boundingBox()
+	if latitude < box.South-geometryEpsilon || latitude > box.North+geometryEpsilon ||
+		longitude < box.West-geometryEpsilon || longitude > box.East+geometryEpsilon {
+		return false
+	}
+
+	vertices := p.vertices()
+	inside := false
+
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[i], vertices[j]
+
+		if onSegment(a, b, latitude, longitude) {
+			return true
+		}
+
+		if (a.Latitude > latitude) != (b.Latitude > latitude) {
+			crossing := a.Longitude + (latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+			if longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// onSegment reports whether a position is within geometryEpsilon of the segment between a and b
+func onSegment(a, b Position, latitude, longitude float64) bool {
+	dLon, dLat := b.Longitude-a.Longitude, b.Latitude-a.Latitude
+
+	// The fraction of the segment where the position is closest to it
+	t := 0.0
+	if length := dLon*dLon + dLat*dLat; length > 0 {
+		t = math.Max(0, math.Min(1, ((longitude-a.Longitude)*dLon+(latitude-a.Latitude)*dLat)/length))
+	}
+
+	return math.Hypot(longitude-(a.Longitude+t*dLon), latitude-(a.Latitude+t*dLat)) <= geometryEpsilon
+}
+
+// text returns the polygon in the text format of the Postgres polygon type, with the
+// longitude as x and the latitude as y
+func (p Polygon) text() string {
+	points := make([]string, 0, len(p.Ring))
+	for _, pos := range p.vertices() {
+		points = append(points, "("+strconv.FormatFloat(pos.Longitude, 'f', -1, 64)+","+strconv.FormatFloat(pos.Latitude, 'f', -1, 64)+")")
+	}
+	return "(" + strings.Join(points, ",") + ")"
+}
+
+// Filter returns the measurements that are within the area
+func (a Area) Filter(depths []models.Snowdepth) []models.Snowdepth {
+	filtered := make([]models.Snowdepth, 0, len(depths))
+	for _, d := range depths {
+		if a.Contains(d.Latitude, d.Longitude) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// boundingBoxes returns the boxes that together enclose the area. A circle that crosses the
+// antimeridian is enclosed by one box on each side of it.
+func (a Area) boundingBoxes() []BoundingBox {
+	if a.Box != nil {
+		return []BoundingBox{*a.Box}
+	}
+
+	if a.Polygon != nil {
+		return []BoundingBox{a.Polygon.boundingBox()}
+	}
+
+	c := a.Circle
+	angle := c.Radius / earthRadiusMeters
+	dLat := c.Radius / metersPerDegree
+
+	south := math.Max(c.Latitude-dLat, -90.0)
+	north := math.Min(c.Latitude+dLat, 90.0)
+
+	// A circle that reaches a pole includes all longitudes
+	cosLat := math.Cos(c.Latitude * math.Pi / 180.0)
+	if angle >= math.Pi/2 || math.Sin(angle) >= cosLat {
+		return []BoundingBox{{South: south, West: -180.0, North: north, East: 180.0}}
+	}
+
+	// The widest longitude span of the circle, which is wider than the span at the latitude
+	// of its center
+	dLon := math.Asin(math.Sin(angle)/cosLat) * 180.0 / math.Pi
+
+	west := c.Longitude - dLon
+	east := c.Longitude + dLon
+
+	switch {
+	case west < -180.0:
+		return []BoundingBox{
+			{South: south, West: west + 360.0, North: north, East: 180.0},
+			{South: south, West: -180.0, North: north, East: east},
+		}
+	case east > 180.0:
+		return []BoundingBox{
+			{South: south, West: west, North: north, East: 180.0},
+			{South: south, West: -180.0, North: north, East: east - 360.0},
+		}
+	}
+
+	return []BoundingBox{{South: south, West: west, North: north, East: east}}
+}
+
+// whereWithin adds conditions that limit a query to the area. The bounding box conditions
+// can use the spatial index, and circles and polygons are then filtered on the great circle
+// distance and the outline of the polygon respectively.
+func whereWithin(tx *gorm.DB, area Area) *gorm.DB {
+	boxes := area.boundingBoxes()
+	conditions := make([]string, 0, len(boxes))
+	args := make([]interface{}, 0, len(boxes)*4)
+
+	for _, box := range boxes {
+		conditions = append(conditions, positionExpr+" <@ box(point(?, ?), point(?, ?))")
+		args = append(args, box.West, box.South, box.East, box.North)
+	}
+
+	tx = tx.Where("("+strings.Join(conditions, " OR ")+")", args...)
+
+	if c := area.Circle; c != nil {
+		tx = tx.Where(
+			"2 * asin(sqrt("+
+				"power(sin(radians(latitude::float8 - ?) / 2), 2) + "+
+				"cos(radians(?)) * cos(radians(latitude::float8)) * power(sin(radians(longitude::float8 - ?) / 2), 2)"+
+				")) <= ?",
+			c.Latitude, c.Latitude, c.Longitude, c.Radius/earthRadiusMeters,
+		)
+	}
+
+	if p := area.Polygon; p != nil {
+		tx = tx.Where(positionExpr+" <@ ?::polygon", p.text())
+	}
+
+	return tx
+}
+
+// distance returns the great circle distance in meters between two positions
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180.0 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+func isValidPosition(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPolygonContains(t *testing.T) {
+	// A concave polygon shaped like a U, open to the north
+	u := Polygon{Ring: []Position{
+		{Latitude: 62.0, Longitude: 17.0},
+		{Latitude: 62.0, Longitude: 17.3},
+		{Latitude: 62.3, Longitude: 17.3},
+		{Latitude: 62.3, Longitude: 17.2},
+		{Latitude: 62.1, Longitude: 17.2},
+		{Latitude: 62.1, Longitude: 17.1},
+		{Latitude: 62.3, Longitude: 17.1},
+		{Latitude: 62.3, Longitude: 17.0},
+		{Latitude: 62.0, Longitude: 17.0},
+	}}
+
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		expected  bool
+	}{
+		{"inside", 62.05, 17.15, true},
+		{"inside an arm", 62.2, 17.05, true},
+		{"in the opening", 62.2, 17.15, false},
+		{"outside the bounding box", 61.9, 17.15, false},
+		{"on a vertex", 62.0, 17.0, true},
+		{"on a southern edge", 62.0, 17.15, true},
+		{"on a northern edge", 62.3, 17.05, true},
+		{"on an eastern edge", 62.15, 17.3, true},
+		{"on an edge of the opening", 62.2, 17.1, true},
+		{"within the tolerance of an edge", 62.0 - geometryEpsilon/2, 17.15, true},
+		{"beyond the tolerance of an edge", 62.0 - 2*geometryEpsilon, 17.15, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if contains := u.contains(tc.latitude, tc.longitude); contains != tc.expected {
+				t.Errorf("expected contains(%v, %v) to be %t", tc.latitude, tc.longitude, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPolygonValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		ring  []Position
+		fails bool
+	}{
+		{"triangle", []Position{{62, 17}, {62, 18}, {63, 17}}, false},
+		{"closed triangle", []Position{{62, 17}, {62, 18}, {63, 17}, {62, 17}}, false},
+		{"too few corners", []Position{{62, 17}, {62, 18}, {62, 17}}, true},
+		{"invalid position", []Position{{62, 17}, {62, 181}, {63, 17}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Area{Polygon: &Polygon{Ring: tc.ring}}.Validate()
+			if tc.fails != (err != nil) {
+				t.Errorf("expected failure to be %t, got %v", tc.fails, err)
+			}
+		})
+	}
+}
+
+func TestInMemoryHistoryWithinPolygon(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	add := func(device string, latitude, longitude float64, depth float64) {
+		t.Helper()
+		when := testStart.Add(time.Duration(depth) * time.Minute).Format(time.RFC3339)
+		if _, _, err := db.AddSnowdepthMeasurement(ctx, &device, latitude, longitude, depth, when); err != nil {
+			t.Fatalf("failed to add measurement: %s", err)
+		}
+	}
+
+	add("inside", 62.1, 17.1, 10)
+	add("edge", 62.0, 17.1, 20)
+	add("outside", 62.1, 17.5, 30)
+
+	triangle := &Polygon{Ring: []Position{
+		{Latitude: 62.0, Longitude: 17.0},
+		{Latitude: 62.0, Longitude: 17.4},
+		{Latitude: 62.4, Longitude: 17.0},
+	}}
+
+	history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{Area: &Area{Polygon: triangle}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if depths := depthsOf(history); !equalDepths(depths, []float32{10, 20}) {
+		t.Errorf("expected the measurements inside and on the edge of the polygon, got %v", depths)
+	}
+}
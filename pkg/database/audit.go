@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// Operations that are recorded in the audit trail
+const (
+	AuditAdd     = "add"
+	AuditCorrect = "correct"
+	AuditRetract = "retract"
+)
+
+// AuditSource describes who made a request and from where, so that manual writes and edits
+// can be recorded in the audit trail
+type AuditSource struct {
+	// Actor is the person that reported or changed a measurement
+	Actor string
+	// Identity is the name of the API key or token that the request was authenticated with
+	Identity  string
+	ClientIP  string
+	UserAgent string
+}
+
+type auditSourceKey struct{}
+
+// WithAuditSource returns a copy of ctx that carries the audit source
+func WithAuditSource(ctx context.Context, source AuditSource) context.Context {
+	return context.WithValue(ctx, auditSourceKey{}, source)
+}
+
+// AuditSourceFromContext returns the audit source that has been added to ctx, or an empty
+// source if there is none
+func AuditSourceFromContext(ctx context.Context) AuditSource {
+	source, _ := ctx.Value(auditSourceKey{}).(AuditSource)
+	return source
+}
+
+// AuditQuery selects entries from the audit trail. Zero values do not restrict the result,
+// and the most recent entries are returned first.
+type AuditQuery struct {
+	From        time.Time
+	To          time.Time
+	Actor       *string
+	SnowdepthID *uint
+	Limit       uint64
+}
+
+func (q AuditQuery) matches(entry models.SnowdepthAuditEntry) bool {
+	if !q.From.IsZero() && entry.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !entry.CreatedAt.Before(q.To) {
+		return false
+	}
+	if q.Actor != nil && entry.Actor != *q.Actor {
+		return false
+	}
+	if q.SnowdepthID != nil && entry.SnowdepthID != *q.SnowdepthID {
+		return false
+	}
+	return true
+}
+
+// newAuditEntry creates an audit entry for an operation on a measurement. The actor from
+// the audit source is used unless another actor is given.
+func newAuditEntry(ctx context.Context, operation, actor string, before, after *models.Snowdepth) models.SnowdepthAuditEntry {
+	source := AuditSourceFromContext(ctx)
+	if actor == "" {
+		actor = source.Actor
+	}
+
+	entry := models.SnowdepthAuditEntry{
+		CreatedAt: time.Now().UTC(),
+		Operation: operation,
+		Actor:     actor,
+		Identity:  source.Identity,
+		ClientIP:  source.ClientIP,
+		UserAgent: source.UserAgent,
+		Before:    models.NewAuditedSnowdepth(before),
+		After:     models.NewAuditedSnowdepth(after),
+	}
+
+	if after != nil {
+		entry.SnowdepthID = after.ID
+	} else if before != nil {
+		entry.SnowdepthID = before.ID
+	}
+
+	return entry
+}
+
+// appendAudit adds an entry to the audit trail as part of the transaction that made the change
+func appendAudit(tx *gorm.DB, entry models.SnowdepthAuditEntry) error {
+	return tx.Create(&entry).Error
+}
+
+// GetAuditTrail returns the entries in the audit trail that match the query
+func (db *myDB) GetAuditTrail(ctx context.Context, query AuditQuery) ([]models.SnowdepthAuditEntry, error) {
+	entries := []models.SnowdepthAuditEntry{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		if !query.From.IsZero() {
+			tx = tx.Where("created_at >= ?", query.From)
+		}
+		if !query.To.IsZero() {
+			tx = tx.Where("created_at < ?", query.To)
+		}
+		if query.Actor != nil {
+			tx = tx.Where("actor = ?", *query.Actor)
+		}
+		if query.SnowdepthID != nil {
+			tx = tx.Where("snowdepth_id = ?", *query.SnowdepthID)
+		}
+		if query.Limit > 0 {
+			tx = tx.Limit(query.Limit)
+		}
+
+		return tx.Order("id desc").Find(&entries).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
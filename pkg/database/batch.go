@@ -0,0 +1,251 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// measurementKey identifies a measurement by the unique combination of device and timestamp
+type measurementKey struct {
+	device    string
+	timestamp int64
+}
+
+func keyOf(device string, timestamp time.Time) measurementKey {
+	return measurementKey{device: device, timestamp: timestamp.UnixNano()}
+}
+
+// AddSnowdepthMeasurements adds a batch of measurements in a single transaction. Measurements
+// that are invalid, quarantined or rejected by the duplicate policy are reported in their
+// IngestResult and do not prevent the rest of the batch from being stored. The returned error is only set if
+// the batch as a whole failed, in which case nothing was stored.
+func (db *myDB) AddSnowdepthMeasurements(ctx context.Context, batch []NewMeasurement) ([]IngestResult, error) {
+	if len(batch) > MaxBatchSize {
+		return nil, newError(ErrValidation, "a batch may contain at most %d measurements, got %d", MaxBatchSize, len(batch))
+	}
+
+	results := make([]IngestResult, len(batch))
+	pending := make([]int, 0, len(batch))
+
+	for idx := range batch {
+		measurement, err := newSnowdepth(batch[idx])
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+
+		results[idx].Measurement = measurement
+		pending = append(pending, idx)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		measurements := make([]*models.Snowdepth, 0, len(pending))
+		for _, idx := range pending {
+			measurements = append(measurements, results[idx].Measurement)
+		}
+
+		if err := db.calibrate(tx, measurements); err != nil {
+			return err
+		}
+
+		rejections, err := db.validate(tx, measurements)
+		if err != nil {
+			return err
+		}
+
+		if err = touchDevices(tx, measurements); err != nil {
+			return err
+		}
+
+		accepted := make([]int, 0, len(pending))
+		measurements = measurements[:0]
+
+		for i, idx := range pending {
+			if rejections[i] != nil {
+				results[idx] = IngestResult{Outcome: IngestQuarantined, Err: rejections[i]}
+			} else {
+				accepted = append(accepted, idx)
+				measurements = append(measurements, results[idx].Measurement)
+			}
+		}
+
+		if len(accepted) == 0 {
+			return nil
+		}
+
+		inserted, err := insertMeasurements(tx, measurements)
+		if err != nil {
+			return err
+		}
+
+		conflicts := []int{}
+		stored := []*models.Snowdepth{}
+
+		for i, idx := range accepted {
+			if inserted[i] {
+				results[idx].Outcome = IngestInserted
+				stored = append(stored, results[idx].Measurement)
+			} else {
+				conflicts = append(conflicts, idx)
+			}
+		}
+
+		if len(conflicts) > 0 {
+			if err = db.resolveConflicts(tx, results, conflicts); err != nil {
+				return err
+			}
+		}
+
+		// Events are published for both inserted and overwritten measurements
+		changed := append([]*models.Snowdepth{}, stored...)
+		for _, idx := range conflicts {
+			if results[idx].Err == nil && results[idx].Outcome == IngestOverwritten {
+				changed = append(changed, results[idx].Measurement)
+			}
+		}
+
+		if err = enqueueStored(tx, changed...); err != nil {
+			return err
+		}
+
+		if err = raiseAlerts(tx, changed...); err != nil {
+			return err
+		}
+
+		return updateLatest(tx, stored...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// insertMeasurements inserts all measurements that do not collide with an existing measurement
+// in a single statement, and reports which of them were inserted. Inserted measurements are
+// updated with their ID and creation time.
+func insertMeasurements(tx *gorm.DB, measurements []*models.Snowdepth) ([]bool, error) {
+	now := time.Now().UTC()
+
+	values := make([]string, 0, len(measurements))
+	args := make([]interface{}, 0, len(measurements)*9)
+
+	for _, m := range measurements {
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, now, now, m.Latitude, m.Longitude, m.Device, m.Depth, m.RawDepth, m.CalibrationID, m.Timestamp)
+	}
+
+	rows, err := tx.Raw(
+		"INSERT INTO snowdepths (created_at, updated_at, latitude, longitude, device, depth, raw_depth, calibration_id, timestamp) VALUES "+
+			strings.Join(values, ", ")+
+			" ON CONFLICT (device, timestamp) DO NOTHING RETURNING id, device, timestamp",
+		args...,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[measurementKey]uint{}
+	for rows.Next() {
+		var id uint
+		var device string
+		var timestamp time.Time
+
+		if err = rows.Scan(&id, &device, &timestamp); err != nil {
+			return nil, err
+		}
+
+		ids[keyOf(device, timestamp)] = id
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	inserted := make([]bool, len(measurements))
+
+	for i, m := range measurements {
+		key := keyOf(m.Device, m.Timestamp)
+
+		// Only the first of several measurements with the same key in a batch is inserted
+		if id, ok := ids[key]; ok {
+			m.ID = id
+			m.CreatedAt = now
+			m.UpdatedAt = now
+			inserted[i] = true
+			delete(ids, key)
+		}
+	}
+
+	return inserted, nil
+}
+
+// resolveConflicts locks the stored measurements that the conflicting measurements collide
+// with and applies the duplicate policy to each of them in turn
+func (db *myDB) resolveConflicts(tx *gorm.DB, results []IngestResult, conflicts []int) error {
+	conditions := make([]string, 0, len(conflicts))
+	args := make([]interface{}, 0, len(conflicts)*2)
+
+	for _, idx := range conflicts {
+		conditions = append(conditions, "(?, ?)")
+		args = append(args, results[idx].Measurement.Device, results[idx].Measurement.Timestamp)
+	}
+
+	rows := []models.Snowdepth{}
+	err := tx.Unscoped().Set("gorm:query_option", "FOR UPDATE").
+		Where("(device, timestamp) IN ("+strings.Join(conditions, ", ")+")", args...).
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	existing := map[measurementKey]*models.Snowdepth{}
+	for i := range rows {
+		existing[keyOf(rows[i].Device, rows[i].Timestamp)] = &rows[i]
+	}
+
+	for _, idx := range conflicts {
+		measurement := results[idx].Measurement
+
+		stored, ok := existing[keyOf(measurement.Device, measurement.Timestamp)]
+		if !ok {
+			// The conflicting measurement was deleted after the insert, so the batch can be retried
+			return newError(ErrUnavailable, "measurement from device %q at %s was concurrently deleted", measurement.Device, measurement.Timestamp)
+		}
+
+		outcome, overwrite, err := resolveDuplicate(db.duplicatePolicy, stored, measurement)
+		results[idx] = IngestResult{Outcome: outcome, Err: err}
+		if err != nil {
+			continue
+		}
+
+		if overwrite {
+			err = tx.Unscoped().Model(stored).Updates(map[string]interface{}{
+				"latitude":       measurement.Latitude,
+				"longitude":      measurement.Longitude,
+				"depth":          measurement.Depth,
+				"raw_depth":      measurement.RawDepth,
+				"calibration_id": measurement.CalibrationID,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := *stored
+		results[idx].Measurement = &result
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+func TestInMemoryBatchResults(t *testing.T) {
+	ctx := context.Background()
+
+	rules := testRules
+	rules.MaxChangePerHour = 10
+
+	db := newTestDatastore(t, DuplicateRejectIfDifferent, rules)
+	addMeasurement(t, db, "a", 0, 10)
+
+	a, b := "a", "b"
+	when := func(offset time.Duration) string {
+		return testStart.Add(offset).Format(time.RFC3339)
+	}
+
+	batch := []NewMeasurement{
+		{&a, 62.39, 17.30, 12, when(time.Hour)},
+		{&a, 62.39, 17.30, 10, when(0)},
+		{&a, 62.39, 17.30, 11, when(0)},
+		{&b, 62.39, 17.30, 2000, when(0)},
+		{&b, 62.39, 17.30, 20, "not a timestamp"},
+		{&b, 62.39, 17.30, 20, when(time.Hour)},
+		{&b, 62.39, 17.30, 20, when(time.Hour)},
+		{&a, 62.39, 17.30, 50, when(90 * time.Minute)},
+	}
+
+	expected := []struct {
+		outcome IngestOutcome
+		err     error
+	}{
+		{IngestInserted, nil},
+		{IngestDuplicate, nil},
+		{IngestConflictIgnored, ErrConflictingMeasurement},
+		{IngestQuarantined, ErrValidation},
+		{IngestInserted, ErrValidation},
+		{IngestInserted, nil},
+		{IngestDuplicate, nil},
+		{IngestQuarantined, ErrValidation},
+	}
+
+	results, err := db.AddSnowdepthMeasurements(ctx, batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+
+	for idx, e := range expected {
+		result := results[idx]
+
+		if e.err == nil && result.Err != nil {
+			t.Errorf("measurement %d: unexpected error: %s", idx, result.Err)
+		} else if e.err != nil && !errors.Is(result.Err, e.err) {
+			t.Errorf("measurement %d: expected error %v, got %v", idx, e.err, result.Err)
+		}
+
+		if result.Err == nil && result.Outcome != e.outcome {
+			t.Errorf("measurement %d: expected outcome %s, got %s", idx, e.outcome, result.Outcome)
+		}
+		if e.outcome == IngestQuarantined && result.Outcome != IngestQuarantined {
+			t.Errorf("measurement %d: expected outcome %s, got %s", idx, e.outcome, result.Outcome)
+		}
+
+		if result.Err == nil && result.Measurement == nil {
+			t.Errorf("measurement %d: expected the stored measurement in the result", idx)
+		}
+	}
+
+	history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if depths := depthsOf(history); !equalDepths(depths, []float32{10, 12, 20}) {
+		t.Errorf("expected depths %v to be stored, got %v", []float32{10, 12, 20}, depths)
+	}
+}
+
+func TestInMemoryBatchIsLimited(t *testing.T) {
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	_, err := db.AddSnowdepthMeasurements(context.Background(), make([]NewMeasurement, MaxBatchSize+1))
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected a validation error for an oversized batch, got %v", err)
+	}
+}
+
+func TestValidateMeasurementsComparesWithinBatch(t *testing.T) {
+	rules := testRules
+	rules.MaxChangePerHour = 10
+
+	stored := &models.Snowdepth{Device: "a", Depth: 10, Timestamp: testStart, Latitude: 62.39, Longitude: 17.30}
+
+	measurement := func(device string, offset time.Duration, depth float32) *models.Snowdepth {
+		return &models.Snowdepth{Device: device, Depth: depth, Timestamp: testStart.Add(offset), Latitude: 62.39, Longitude: 17.30}
+	}
+
+	tests := []struct {
+		name         string
+		measurements []*models.Snowdepth
+		expected     []string
+	}{
+		{
+			name:         "compared with the stored measurement",
+			measurements: []*models.Snowdepth{measurement("a", time.Hour, 25)},
+			expected:     []string{RuleRateOfChange},
+		},
+		{
+			name:         "compared with an accepted measurement in the batch",
+			measurements: []*models.Snowdepth{measurement("a", 2*time.Hour, 30), measurement("a", time.Hour, 20)},
+			expected:     []string{"", ""},
+		},
+		{
+			name:         "rejected measurements are not compared with",
+			measurements: []*models.Snowdepth{measurement("a", time.Hour, 50), measurement("a", 2*time.Hour, 25)},
+			expected:     []string{RuleRateOfChange, ""},
+		},
+		{
+			name:         "devices are checked separately",
+			measurements: []*models.Snowdepth{measurement("b", time.Hour, 500), measurement("a", time.Hour, 15)},
+			expected:     []string{"", ""},
+		},
+		{
+			name:         "manual measurements are not compared",
+			measurements: []*models.Snowdepth{measurement("", time.Hour, 500)},
+			expected:     []string{""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rejections := rules.validateMeasurements(tc.measurements, func(m *models.Snowdepth) *models.Snowdepth {
+				if m.Device == stored.Device && stored.Timestamp.Before(m.Timestamp) {
+					return stored
+				}
+				return nil
+			})
+
+			for idx, expected := range tc.expected {
+				rule := ""
+				if rejections[idx] != nil {
+					rule = rejections[idx].Rule
+				}
+				if rule != expected {
+					t.Errorf("measurement %d: expected rule %q, got %q", idx, expected, rule)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package database
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// calibrationLockID together with the device serialises changes to the calibrations of a device
+const calibrationLockID = 7382196
+
+// Calibration contains the values of a new or changed device calibration. The depth is computed
+// from a raw value as raw*Scale + Offset, or as (MountingHeight - raw)*Scale + Offset for sensors
+// that report the distance to the surface.
+type Calibration struct {
+	Device         string
+	MountingHeight *float64
+	Offset         float64
+	Scale          float64
+	ValidFrom      time.Time
+	ValidTo        *time.Time
+}
+
+func (c Calibration) validate() error {
+	if strings.TrimSpace(c.Device) == "" {
+		return newError(ErrValidation, "a calibration must belong to a device")
+	}
+
+	if c.Scale == 0 || math.IsNaN(c.Scale) || math.IsInf(c.Scale, 0) {
+		return newError(ErrValidation, "the scale of a calibration must be a non zero number")
+	}
+
+	if math.IsNaN(c.Offset) || math.IsInf(c.Offset, 0) {
+		return newError(ErrValidation, "the offset of a calibration must be a number")
+	}
+
+	if c.MountingHeight != nil && (*c.MountingHeight <= 0 || math.IsInf(*c.MountingHeight, 0)) {
+		return newError(ErrValidation, "the mounting height of a calibration must be positive")
+	}
+
+	if c.ValidFrom.IsZero() {
+		return newError(ErrValidation, "a calibration must have a start time")
+	}
+
+	if c.ValidTo != nil && !c.ValidTo.After(c.ValidFrom) {
+		return newError(ErrValidation, "a calibration must end after it starts")
+	}
+
+	return nil
+}
+
+func (c Calibration) apply(calibration *models.DeviceCalibration) {
+	calibration.Device = c.Device
+	calibration.MountingHeight = c.MountingHeight
+	calibration.DepthOffset = c.Offset
+	calibration.DepthScale = c.Scale
+	calibration.ValidFrom = c.ValidFrom.UTC().Truncate(time.Microsecond)
+	calibration.ValidTo = nil
+
+	if c.ValidTo != nil {
+		validTo := c.ValidTo.UTC().Truncate(time.Microsecond)
+		calibration.ValidTo = &validTo
+	}
+}
+
+// CalibrationChange is the result of adding, changing or deleting a calibration
+type CalibrationChange struct {
+	Calibration *models.DeviceCalibration
+	// Recomputed is the number of stored measurements that had their depth recomputed
+	Recomputed int64
+}
+
+// timeSpan is a time interval that is open ended if to is nil
+type timeSpan struct {
+	from time.Time
+	to   *time.Time
+}
+
+func spanOf(c *models.DeviceCalibration) timeSpan {
+	return timeSpan{from: c.ValidFrom, to: c.ValidTo}
+}
+
+// union returns the smallest span that covers both spans
+func (s timeSpan) union(other timeSpan) timeSpan {
+	result := s
+	if other.from.Before(result.from) {
+		result.from = other.from
+	}
+	if result.to != nil && (other.to == nil || other.to.After(*result.to)) {
+		result.to = other.to
+	}
+	return result
+}
+
+func (s timeSpan) overlaps(other timeSpan) bool {
+	return (s.to == nil || other.from.Before(*s.to)) && (other.to == nil || s.from.Before(*other.to))
+}
+
+// calibrate computes the depth of each measurement from its raw value, using the calibration
+// of its device that is valid at the time of the measurement, if there is one
+func calibrate(measurements []*models.Snowdepth, calibrations []models.DeviceCalibration) {
+	for _, m := range measurements {
+		m.Depth = m.RawDepth
+		m.CalibrationID = nil
+
+		for idx := range calibrations {
+			c := &calibrations[idx]
+			if c.Device == m.Device && c.Covers(m.Timestamp) {
+				id := c.ID
+				m.Depth = float32(c.Apply(float64(m.RawDepth)))
+				m.CalibrationID = &id
+				break
+			}
+		}
+	}
+}
+
+// calibrate loads the calibrations of the devices that new measurements come from and
+// computes their depths
+func (db *myDB) calibrate(tx *gorm.DB, measurements []*models.Snowdepth) error {
+	devices := []string{}
+	seen := map[string]bool{}
+
+	for _, m := range measurements {
+		if m.Device != "" && !seen[m.Device] {
+			seen[m.Device] = true
+			devices = append(devices, m.Device)
+		}
+	}
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	calibrations := []models.DeviceCalibration{}
+	if err := tx.Where("device IN (?)", devices).Find(&calibrations).Error; err != nil {
+		return err
+	}
+
+	calibrate(measurements, calibrations)
+
+	return nil
+}
+
+// GetDeviceCalibrations returns the calibrations of a device ordered by the time they start
+func (db *myDB) GetDeviceCalibrations(ctx context.Context, device string) ([]models.DeviceCalibration, error) {
+	calibrations := []models.DeviceCalibration{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.Where("device = ?", device).Order("valid_from").Find(&calibrations).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return calibrations, nil
+}
+
+// AddDeviceCalibration adds a calibration that must not overlap any other calibration of the
+// device. If recompute is set, the depths of stored measurements during the time that the
+// calibration is valid are recomputed from their raw values.
+func (db *myDB) AddDeviceCalibration(ctx context.Context, input Calibration, recompute bool) (CalibrationChange, error) {
+	if err := input.validate(); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	calibration := &models.DeviceCalibration{}
+	input.apply(calibration)
+
+	result := CalibrationChange{Calibration: calibration}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := lockCalibrations(tx, calibration.Device); err != nil {
+			return err
+		}
+
+		if err := checkOverlap(tx, calibration); err != nil {
+			return err
+		}
+
+		if err := tx.Create(calibration).Error; err != nil {
+			return err
+		}
+
+		if recompute {
+			var err error
+			result.Recomputed, err = recomputeDepths(tx, calibration.Device, spanOf(calibration))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CalibrationChange{}, err
+	}
+
+	return result, nil
+}
+
+// UpdateDeviceCalibration changes a calibration. If recompute is set, the depths of stored
+// measurements during the time that either the old or the new calibration is valid are
+// recomputed from their raw values.
+func (db *myDB) UpdateDeviceCalibration(ctx context.Context, id uint, input Calibration, recompute bool) (CalibrationChange, error) {
+	if err := input.validate(); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	calibration := &models.DeviceCalibration{}
+	result := CalibrationChange{Calibration: calibration}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := lockCalibrations(tx, input.Device); err != nil {
+			return err
+		}
+
+		if err := tx.First(calibration, id).Error; err != nil {
+			return err
+		}
+
+		if calibration.Device != input.Device {
+			return newError(ErrValidation, "calibration %d belongs to device %q and can not be moved to another device", id, calibration.Device)
+		}
+
+		affected := spanOf(calibration)
+		input.apply(calibration)
+		affected = affected.union(spanOf(calibration))
+
+		if err := checkOverlap(tx, calibration); err != nil {
+			return err
+		}
+
+		if err := tx.Save(calibration).Error; err != nil {
+			return err
+		}
+
+		if recompute {
+			var err error
+			result.Recomputed, err = recomputeDepths(tx, calibration.Device, affected)
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CalibrationChange{}, err
+	}
+
+	return result, nil
+}
+
+// DeleteDeviceCalibration removes a calibration. If recompute is set, stored measurements
+// during the time that it was valid get their raw values back as depths.
+func (db *myDB) DeleteDeviceCalibration(ctx context.Context, id uint, recompute bool) (CalibrationChange, error) {
+	calibration := &models.DeviceCalibration{}
+	result := CalibrationChange{Calibration: calibration}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := tx.First(calibration, id).Error; err != nil {
+			return err
+		}
+
+		if err := lockCalibrations(tx, calibration.Device); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(calibration).Error; err != nil {
+			return err
+		}
+
+		if recompute {
+			var err error
+			result.Recomputed, err = recomputeDepths(tx, calibration.Device, spanOf(calibration))
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CalibrationChange{}, err
+	}
+
+	return result, nil
+}
+
+// lockCalibrations serialises changes to the calibrations of a device until the end of the
+// transaction, so that concurrent changes can not introduce overlapping calibrations
+func lockCalibrations(tx *gorm.DB, device string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", calibrationLockID, device).Error
+}
+
+// checkOverlap fails with ErrValidation if the calibration overlaps another calibration of
+// the same device
+func checkOverlap(tx *gorm.DB, calibration *models.DeviceCalibration) error {
+	tx = tx.Where("device = ? AND id <> ?", calibration.Device, calibration.ID).
+		Where("valid_to IS NULL OR valid_to > ?", calibration.ValidFrom)
+
+	if calibration.ValidTo != nil {
+		tx = tx.Where("valid_from < ?", *calibration.ValidTo)
+	}
+
+	existing := models.DeviceCalibration{}
+	err := tx.First(&existing).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return newError(ErrValidation, "the calibration overlaps calibration %d of device %q", existing.ID, calibration.Device)
+}
+
+// recomputeDepths computes the depths of the stored measurements from a device during a span
+// of time from their raw values, using the calibrations that are valid at the time of each
+// measurement. Measurements that have been corrected manually keep their corrected depth.
+func recomputeDepths(tx *gorm.DB, device string, span timeSpan) (int64, error) {
+	conditions := "m.device = ? AND m.timestamp >= ? AND m.changed_by = ''"
+	args := []interface{}{device, span.from}
+
+	if span.to != nil {
+		conditions += " AND m.timestamp < ?"
+		args = append(args, *span.to)
+	}
+
+	res := tx.Exec(`
+		UPDATE snowdepths AS s SET depth = calibrated.depth, calibration_id = calibrated.calibration_id
+		FROM (
+			SELECT m.id, c.id AS calibration_id,
+				CASE
+					WHEN c.id IS NULL THEN m.raw_depth
+					WHEN c.mounting_height IS NULL THEN m.raw_depth * c.depth_scale + c.depth_offset
+					ELSE (c.mounting_height - m.raw_depth) * c.depth_scale + c.depth_offset
+				END AS depth
+			FROM snowdepths AS m
+			LEFT JOIN device_calibrations AS c ON c.device = m.device
+				AND c.valid_from <= m.timestamp AND (c.valid_to IS NULL OR c.valid_to > m.timestamp)
+			WHERE `+conditions+`
+		) AS calibrated
+		WHERE s.id = calibrated.id`, args...)
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCalibrationValidate(t *testing.T) {
+	validTo := testStart.Add(time.Hour)
+	before := testStart.Add(-time.Hour)
+	height := 100.0
+	noHeight := 0.0
+
+	tests := []struct {
+		name        string
+		calibration Calibration
+		fails       bool
+	}{
+		{"valid", Calibration{Device: "a", Scale: 1, ValidFrom: testStart, ValidTo: &validTo}, false},
+		{"open ended distance sensor", Calibration{Device: "a", MountingHeight: &height, Scale: 1, ValidFrom: testStart}, false},
+		{"no device", Calibration{Device: " ", Scale: 1, ValidFrom: testStart}, true},
+		{"zero scale", Calibration{Device: "a", ValidFrom: testStart}, true},
+		{"zero mounting height", Calibration{Device: "a", MountingHeight: &noHeight, Scale: 1, ValidFrom: testStart}, true},
+		{"no start", Calibration{Device: "a", Scale: 1}, true},
+		{"ends before it starts", Calibration{Device: "a", Scale: 1, ValidFrom: testStart, ValidTo: &before}, true},
+		{"ends when it starts", Calibration{Device: "a", Scale: 1, ValidFrom: testStart, ValidTo: &testStart}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.calibration.validate()
+			if tc.fails && !errors.Is(err, ErrValidation) {
+				t.Errorf("expected a validation error, got %v", err)
+			} else if !tc.fails && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestTimeSpanOverlaps(t *testing.T) {
+	at := func(hours int) *time.Time {
+		when := testStart.Add(time.Duration(hours) * time.Hour)
+		return &when
+	}
+
+	tests := []struct {
+		name     string
+		a, b     timeSpan
+		expected bool
+	}{
+		{"disjoint", timeSpan{*at(0), at(1)}, timeSpan{*at(2), at(3)}, false},
+		{"adjacent", timeSpan{*at(0), at(1)}, timeSpan{*at(1), at(2)}, false},
+		{"overlapping", timeSpan{*at(0), at(2)}, timeSpan{*at(1), at(3)}, true},
+		{"contained", timeSpan{*at(0), at(3)}, timeSpan{*at(1), at(2)}, true},
+		{"open ended after", timeSpan{*at(0), nil}, timeSpan{*at(5), at(6)}, true},
+		{"open ended before", timeSpan{*at(5), nil}, timeSpan{*at(0), at(5)}, false},
+		{"both open ended", timeSpan{*at(0), nil}, timeSpan{*at(5), nil}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.a.overlaps(tc.b) != tc.expected || tc.b.overlaps(tc.a) != tc.expected {
+				t.Errorf("expected overlap to be %t", tc.expected)
+			}
+		})
+	}
+}
+
+func TestInMemoryCalibrationsMustNotOverlap(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	validTo := testStart.Add(2 * time.Hour)
+	first, err := db.AddDeviceCalibration(ctx, Calibration{Device: "a", Scale: 1, ValidFrom: testStart, ValidTo: &validTo}, false)
+	if err != nil {
+		t.Fatalf("failed to add calibration: %s", err)
+	}
+
+	overlapping := Calibration{Device: "a", Scale: 2, ValidFrom: testStart.Add(time.Hour)}
+	if _, err := db.AddDeviceCalibration(ctx, overlapping, false); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an overlapping calibration to be refused, got %v", err)
+	}
+
+	adjacent := Calibration{Device: "a", Scale: 2, ValidFrom: validTo}
+	second, err := db.AddDeviceCalibration(ctx, adjacent, false)
+	if err != nil {
+		t.Fatalf("expected an adjacent calibration to be added, got %s", err)
+	}
+
+	if _, err := db.AddDeviceCalibration(ctx, Calibration{Device: "b", Scale: 2, ValidFrom: testStart}, false); err != nil {
+		t.Errorf("expected calibrations of other devices not to overlap, got %s", err)
+	}
+
+	extended := Calibration{Device: "a", Scale: 1, ValidFrom: testStart}
+	if _, err := db.UpdateDeviceCalibration(ctx, first.Calibration.ID, extended, false); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an update that overlaps another calibration to be refused, got %v", err)
+	}
+
+	if _, err := db.UpdateDeviceCalibration(ctx, second.Calibration.ID, Calibration{Device: "a", Scale: 3, ValidFrom: validTo}, false); err != nil {
+		t.Errorf("expected a calibration not to overlap itself, got %s", err)
+	}
+
+	calibrations, err := db.GetDeviceCalibrations(ctx, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(calibrations) != 2 || calibrations[0].ID != first.Calibration.ID || calibrations[1].DepthScale != 3 {
+		t.Errorf("expected the two calibrations of the device, got %+v", calibrations)
+	}
+}
+
+func TestInMemoryUnknownCalibration(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	if _, err := db.UpdateDeviceCalibration(ctx, 42, Calibration{Device: "a", Scale: 1, ValidFrom: testStart}, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown calibration not to be found when updated, got %v", err)
+	}
+	if _, err := db.DeleteDeviceCalibration(ctx, 42, true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown calibration not to be found when deleted, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/rs/zerolog"
+)
+
+const maxConnectBackoff = 30 * time.Second
+
+type connectionConfig struct {
+	host     string
+	user     string
+	dbName   string
+	password string
+	sslMode  string
+
+	connectTimeout  time.Duration
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+func (cfg connectionConfig) uri() string {
+	return fmt.Sprintf(
+		"host=%s user=%s dbname=%s sslmode=%s password=%s",
+		cfg.host, cfg.user, cfg.dbName, cfg.sslMode, cfg.password,
+	)
+}
+
+// loadConnectionConfig reads the connection and pool settings from the environment
+func loadConnectionConfig() (connectionConfig, error) {
+	var err error
+
+	cfg := connectionConfig{
+		host:     os.Getenv("SNOWDEPTH_DB_HOST"),
+		user:     os.Getenv("SNOWDEPTH_DB_USER"),
+		dbName:   os.Getenv("SNOWDEPTH_DB_NAME"),
+		password: os.Getenv("SNOWDEPTH_DB_PASSWORD"),
+		sslMode:  getEnv("SNOWDEPTH_DB_SSLMODE", "require"),
+	}
+
+	if cfg.connectTimeout, err = getEnvDuration("SNOWDEPTH_DB_CONNECT_TIMEOUT", 2*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.maxOpenConns, err = getEnvInt("SNOWDEPTH_DB_MAX_OPEN_CONNS", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.maxIdleConns, err = getEnvInt("SNOWDEPTH_DB_MAX_IDLE_CONNS", 5); err != nil {
+		return cfg, err
+	}
+	if cfg.connMaxLifetime, err = getEnvDuration("SNOWDEPTH_DB_CONN_MAX_LIFETIME", 30*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.connMaxIdleTime, err = getEnvDuration("SNOWDEPTH_DB_CONN_MAX_IDLE_TIME", 5*time.Minute); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// openConnection connects to the database, retrying with an exponential backoff until
+// SNOWDEPTH_DB_CONNECT_TIMEOUT has passed, and configures the connection pool
+func openConnection(logger zerolog.Logger) (*gorm.DB, error) {
+	cfg, err := loadConnectionConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logger = logger.With().Str("host", cfg.host).Logger()
+
+	deadline := time.Now().Add(cfg.connectTimeout)
+	backoff := time.Second
+
+	for attempt := 1; ; attempt++ {
+		logger.Info().Int("attempt", attempt).Msg("Connecting to database host ...")
+
+		conn, err := gorm.Open("postgres", cfg.uri())
+		if err == nil {
+			db := conn.DB()
+			db.SetMaxOpenConns(cfg.maxOpenConns)
+			db.SetMaxIdleConns(cfg.maxIdleConns)
+			db.SetConnMaxLifetime(cfg.connMaxLifetime)
+			db.SetConnMaxIdleTime(cfg.connMaxIdleTime)
+			return conn, nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		logger.Warn().Err(err).Dur("retryIn", backoff).Msg("failed to connect to database")
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return i, nil
+}
+
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// operation identifies a kind of datastore call, each with its own configurable timeout
+type operation int
+
+const (
+	opWrite operation = iota
+	opRead
+	opStatistics
+	opRetention
+	opRelay
+)
+
+// queryTimeouts limits how long each kind of operation may run. A zero timeout only
+// relies on the deadline of the caller's context, if any.
+type queryTimeouts map[operation]time.Duration
+
+// loadQueryTimeouts reads the timeouts from SNOWDEPTH_DB_WRITE_TIMEOUT, SNOWDEPTH_DB_READ_TIMEOUT,
+// SNOWDEPTH_DB_STATISTICS_TIMEOUT, SNOWDEPTH_DB_RETENTION_TIMEOUT and SNOWDEPTH_DB_RELAY_TIMEOUT
+func loadQueryTimeouts() (queryTimeouts, error) {
+	defaults := []struct {
+		op       operation
+		key      string
+		fallback time.Duration
+	}{
+		{opWrite, "SNOWDEPTH_DB_WRITE_TIMEOUT", 5 * time.Second},
+		{opRead, "SNOWDEPTH_DB_READ_TIMEOUT", 10 * time.Second},
+		{opStatistics, "SNOWDEPTH_DB_STATISTICS_TIMEOUT", 30 * time.Second},
+		{opRetention, "SNOWDEPTH_DB_RETENTION_TIMEOUT", 15 * time.Minute},
+		{opRelay, "SNOWDEPTH_DB_RELAY_TIMEOUT", time.Minute},
+	}
+
+	timeouts := queryTimeouts{}
+
+	for _, d := range defaults {
+		timeout, err := getEnvDuration(d.key, d.fallback)
+		if err != nil {
+			return nil, err
+		}
+		if timeout < 0 {
+			return nil, fmt.Errorf("%s must not be negative", d.key)
+		}
+		timeouts[d.op] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// withTimeout derives a context that is cancelled when the timeout for the operation expires
+func (t queryTimeouts) withTimeout(ctx context.Context, op operation) (context.Context, context.CancelFunc) {
+	if timeout := t[op]; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+var tracer = otel.Tracer("github.com/diwise/api-snowdepth/pkg/database")
+
+// statementLogger receives every statement that gorm executes on the handle of an operation.
+// Gorm v1 has no support for contexts of its own, so this is what records a span for each
+// statement as a child of the span in ctx, along with the tenant of the operation. Statements
+// are passed on to next, if set, to be logged as well.
+type statementLogger struct {
+	ctx  context.Context
+	next interface{ Print(v ...interface{}) }
+	err  error
+}
+
+// Print is called by gorm with "log" and the file and line, followed by an error if the
+// statement failed, and then with "sql", the file and line, the duration and the statement
+func (l *statementLogger) Print(v ...interface{}) {
+	if len(v) > 2 {
+		switch v[0] {
+		case "log":
+			if err, ok := v[2].(error); ok {
+				l.err = err
+			}
+		case "sql":
+			if len(v) < 4 {
+				break
+			}
+			duration, _ := v[2].(time.Duration)
+			query, _ := v[3].(string)
+			recordStatement(l.ctx, query, duration, l.err)
+			l.err = nil
+		}
+	}
+
+	if l.next != nil {
+		l.next.Print(v...)
+	}
+}
+
+// recordStatement records a span for an SQL statement that ended just now if ctx is part of a
+// trace. The span is named after the kind of statement, and the statement itself is recorded
+// without its arguments.
+func recordStatement(ctx context.Context, query string, duration time.Duration, err error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	operation := strings.ToUpper(strings.SplitN(strings.TrimSpace(query), " ", 2)[0])
+	attributes := []attribute.KeyValue{
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationKey.String(operation),
+		semconv.DBStatementKey.String(query),
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		attributes = append(attributes, attribute.String("snowdepth.tenant", tenant))
+	}
+
+	end := time.Now()
+	_, span := tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-duration)),
+		trace.WithAttributes(attributes...),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// read runs fn within a read only transaction that is bound to ctx and the timeout of the
+// operation
+func (db *myDB) read(ctx context.Context, op operation, fn func(tx *gorm.DB) error) error {
+	return db.run(ctx, op, &sql.TxOptions{ReadOnly: true}, fn)
+}
+
+// transaction runs fn within a transaction that is bound to ctx and the timeout of the
+// operation. The transaction is committed if fn succeeds and rolled back otherwise.
+func (db *myDB) transaction(ctx context.Context, op operation, fn func(tx *gorm.DB) error) error {
+	return db.run(ctx, op, nil, fn)
+}
+
+// run begins a transaction on the handle of the datastore with ctx, so that a cancelled request
+// or an expired timeout aborts a statement that is already running in the database. The tenant
+// in ctx, if any, is made available to the statements of the transaction as the setting
+// snowdepth.tenant.
+func (db *myDB) run(ctx context.Context, op operation, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error {
+	ctx, cancel := db.timeouts.withTimeout(ctx, op)
+	defer cancel()
+
+	tx := db.impl.BeginTx(ctx, opts)
+	if tx.Error != nil {
+		return classifyContextError(ctx, tx.Error)
+	}
+
+	logger := &statementLogger{ctx: ctx}
+	if db.logMode {
+		logger.next = gorm.Logger{LogWriter: log.New(os.Stdout, "\r\n", 0)}
+	}
+	tx.SetLogger(logger)
+
+	var err error
+	if tenant, ok := TenantFromContext(ctx); ok {
+		err = tx.Exec("SELECT set_config('snowdepth.tenant', ?, true)", tenant).Error
+	}
+	if err == nil {
+		err = fn(tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return classifyContextError(ctx, err)
+	}
+
+	return classifyContextError(ctx, tx.Commit().Error)
+}
+
+// checkContext returns an error if ctx has already been cancelled
+func checkContext(ctx context.Context) error {
+	return classifyContextError(ctx, ctx.Err())
+}
+
+// classifyContextError attributes an error to ctx if it has expired or been cancelled, since
+// the error reported by the driver then only describes the aborted statement. Other errors
+// are classified as usual.
+func classifyContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &Error{kind: ErrUnavailable, err: fmt.Errorf("operation timed out: %w", err)}
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w: %s", context.Canceled, err.Error())
+	}
+
+	return classifyError(err)
+}
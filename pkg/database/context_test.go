@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	spanRecorder     = tracetest.NewSpanRecorder()
+	spanRecorderOnce sync.Once
+)
+
+// testSpanRecorder installs a tracer provider that records the spans of the package. The global
+// provider can only be set once for the tracer of the package, so it is shared by all tests.
+func testSpanRecorder() (*tracetest.SpanRecorder, trace.TracerProvider) {
+	spanRecorderOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+	return spanRecorder, otel.GetTracerProvider()
+}
+
+func TestStatementLoggerRecordsSpans(t *testing.T) {
+	recorder, provider := testSpanRecorder()
+	before := len(recorder.Ended())
+
+	ctx, parent := provider.Tracer("test").Start(WithTenant(context.Background(), "skelleftea"), "Datastore.Test")
+
+	logger := &statementLogger{ctx: ctx}
+	logger.Print("log", "outbox.go:10", errors.New("deadlock detected"))
+	logger.Print("sql", "outbox.go:10", 20*time.Millisecond, "UPDATE outbox SET attempts = attempts + 1", []interface{}{}, int64(0))
+	logger.Print("sql", "outbox.go:20", 10*time.Millisecond, " select * from outbox", []interface{}{}, int64(2))
+	parent.End()
+
+	spans := recorder.Ended()[before:]
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	tests := []struct {
+		name   string
+		status codes.Code
+	}{
+		{"UPDATE", codes.Error},
+		{"SELECT", codes.Unset},
+	}
+
+	for idx, tc := range tests {
+		span := spans[idx]
+		if span.Name() != tc.name || span.Status().Code != tc.status {
+			t.Errorf("expected span %s with status %s, got %s with %s", tc.name, tc.status, span.Name(), span.Status().Code)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected span %s to be a child of the operation", span.Name())
+		}
+		if !span.EndTime().After(span.StartTime()) {
+			t.Errorf("expected span %s to cover the duration of the statement", span.Name())
+		}
+		if !hasAttribute(span.Attributes(), attribute.String("snowdepth.tenant", "skelleftea")) {
+			t.Errorf("expected span %s to record the tenant, got %v", span.Name(), span.Attributes())
+		}
+	}
+}
+
+func TestStatementLoggerIgnoresUntracedStatements(t *testing.T) {
+	recorder, _ := testSpanRecorder()
+	before := len(recorder.Ended())
+
+	logger := &statementLogger{ctx: context.Background()}
+	logger.Print("sql", "outbox.go:20", 10*time.Millisecond, "SELECT 1", []interface{}{}, int64(1))
+
+	if len(recorder.Ended()) != before {
+		t.Errorf("expected no spans outside of a trace")
+	}
+}
+
+func hasAttribute(attributes []attribute.KeyValue, expected attribute.KeyValue) bool {
+	for _, a := range attributes {
+		if a == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// MeasurementChange identifies who changed a measurement and why
+type MeasurementChange struct {
+	ChangedBy string
+	Reason    string
+}
+
+func (c MeasurementChange) validate() error {
+	if strings.TrimSpace(c.ChangedBy) == "" {
+		return newError(ErrValidation, "the author of a change must be specified")
+	}
+	if strings.TrimSpace(c.Reason) == "" {
+		return newError(ErrValidation, "the reason for a change must be specified")
+	}
+	return nil
+}
+
+// SnowdepthCorrection contains a new depth and/or position for a measurement. The latitude
+// and longitude must either both be set or both be nil.
+type SnowdepthCorrection struct {
+	MeasurementChange
+	Depth     *float64
+	Latitude  *float64
+	Longitude *float64
+}
+
+func (c SnowdepthCorrection) validate() error {
+	if err := c.MeasurementChange.validate(); err != nil {
+		return err
+	}
+
+	if (c.Latitude == nil) != (c.Longitude == nil) {
+		return newError(ErrValidation, "a corrected position must have both a latitude and a longitude")
+	}
+
+	if c.Depth == nil && c.Latitude == nil {
+		return newError(ErrValidation, "a correction must change the depth or the position")
+	}
+
+	if c.Latitude != nil && !isValidPosition(*c.Latitude, *c.Longitude) {
+		return newError(ErrValidation, "the corrected position must be a valid WGS84 position")
+	}
+
+	return nil
+}
+
+// apply updates the measurement with the corrected values and returns the changed columns
+func (c SnowdepthCorrection) apply(measurement *models.Snowdepth) map[string]interface{} {
+	measurement.ChangedBy = c.ChangedBy
+	measurement.ChangeReason = c.Reason
+
+	updates := map[string]interface{}{
+		"changed_by":    c.ChangedBy,
+		"change_reason": c.Reason,
+	}
+
+	if c.Depth != nil {
+		measurement.Depth = float32(*c.Depth)
+		updates["depth"] = measurement.Depth
+	}
+
+	if c.Latitude != nil {
+		measurement.Latitude = *c.Latitude
+		measurement.Longitude = *c.Longitude
+		updates["latitude"] = measurement.Latitude
+		updates["longitude"] = measurement.Longitude
+	}
+
+	return updates
+}
+
+// CorrectSnowdepthMeasurement changes the depth and/or position of a stored measurement and
+// records the change in the audit trail. The corrected measurement is published and evaluated
+// by the alert rules as if it had been stored again. Retracted measurements can not be corrected and are reported as ErrNotFound.
+func (db *myDB) CorrectSnowdepthMeasurement(ctx context.Context, id uint, correction SnowdepthCorrection) (*models.Snowdepth, error) {
+	if err := correction.validate(); err != nil {
+		return nil, err
+	}
+
+	measurement := &models.Snowdepth{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		err := tx.Set("gorm:query_option", "FOR UPDATE").First(measurement, id).Error
+		if err != nil {
+			return err
+		}
+
+		before := *measurement
+		updates := correction.apply(measurement)
+
+		if err = db.rules.checkCorrection(measurement); err != nil {
+			return err
+		}
+
+		err = tx.Model(measurement).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		if err = enqueueStored(tx, measurement); err != nil {
+			return err
+		}
+
+		if err = raiseAlerts(tx, measurement); err != nil {
+			return err
+		}
+
+		return appendAudit(tx, newAuditEntry(ctx, AuditCorrect, correction.ChangedBy, &before, measurement))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// RetractSnowdepthMeasurement soft deletes a measurement, so that it is no longer returned
+// from any query, and records the retraction in the audit trail. If it was the latest measurement from its device, the previous one takes
+// its place.
+func (db *myDB) RetractSnowdepthMeasurement(ctx context.Context, id uint, change MeasurementChange) (*models.Snowdepth, error) {
+	if err := change.validate(); err != nil {
+		return nil, err
+	}
+
+	measurement := &models.Snowdepth{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		err := tx.Set("gorm:query_option", "FOR UPDATE").First(measurement, id).Error
+		if err != nil {
+			return err
+		}
+
+		before := *measurement
+
+		now := time.Now().UTC()
+		measurement.ChangedBy = change.ChangedBy
+		measurement.ChangeReason = change.Reason
+		measurement.DeletedAt = &now
+
+		err = tx.Model(measurement).Updates(map[string]interface{}{
+			"changed_by":    change.ChangedBy,
+			"change_reason": change.Reason,
+			"deleted_at":    now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err = replaceLatest(tx, measurement); err != nil {
+			return err
+		}
+
+		return appendAudit(tx, newAuditEntry(ctx, AuditRetract, change.ChangedBy, &before, measurement))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return measurement, nil
+}
+
+// replaceLatest makes the most recent remaining measurement from the device the latest one,
+// if the retracted measurement was the latest one
+func replaceLatest(tx *gorm.DB, retracted *models.Snowdepth) error {
+	if retracted.Device == "" {
+		return nil
+	}
+
+	res := tx.Exec("DELETE FROM latest_snowdepths WHERE snowdepth_id = ?", retracted.ID)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return res.Error
+	}
+
+	previous := &models.Snowdepth{}
+	err := tx.Where("device = ?", retracted.Device).Order("timestamp desc").First(previous).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return updateLatest(tx, previous)
+}
@@ -2,10 +2,12 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -16,12 +18,80 @@ import (
 	"github.com/diwise/api-snowdepth/pkg/models"
 )
 
-// Datastore is an interface that is used to inject the database into different handlers to improve testability
+// Datastore is an interface that is used to inject the database into different handlers to improve testability.
+// All operations are aborted when their context is cancelled or when the timeout for the operation expires.
 type Datastore interface {
-	AddManualSnowdepthMeasurement(latitude, longitude, depth float64) (*models.Snowdepth, error)
-	AddSnowdepthMeasurement(device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, error)
-	GetLatestSnowdepths() ([]models.Snowdepth, error)
-	GetLatestSnowdepthsForDevice(device string) ([]models.Snowdepth, error)
+	AddManualSnowdepthMeasurement(ctx context.Context, latitude, longitude, depth float64) (*models.Snowdepth, error)
+	AddSnowdepthMeasurement(ctx context.Context, device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, IngestOutcome, error)
+	AddSnowdepthMeasurements(ctx context.Context, batch []NewMeasurement) ([]IngestResult, error)
+	CorrectSnowdepthMeasurement(ctx context.Context, id uint, correction SnowdepthCorrection) (*models.Snowdepth, error)
+	RetractSnowdepthMeasurement(ctx context.Context, id uint, change MeasurementChange) (*models.Snowdepth, error)
+	GetLatestSnowdepths(ctx context.Context) ([]models.Snowdepth, error)
+	GetLatestSnowdepthsForDevice(ctx context.Context, device string) ([]models.Snowdepth, error)
+	GetSnowdepthHistory(ctx context.Context, query SnowdepthQuery) ([]models.Snowdepth, error)
+	GetSnowdepthStatistics(ctx context.Context, device *string, from, to time.Time, interval AggregationInterval) ([]models.SnowdepthStatistics, error)
+
+	GetAuditTrail(ctx context.Context, query AuditQuery) ([]models.SnowdepthAuditEntry, error)
+	GetQuarantinedSnowdepths(ctx context.Context, query QuarantineQuery) ([]models.QuarantinedSnowdepth, error)
+
+	GetDeviceCalibrations(ctx context.Context, device string) ([]models.DeviceCalibration, error)
+	AddDeviceCalibration(ctx context.Context, calibration Calibration, recompute bool) (CalibrationChange, error)
+	UpdateDeviceCalibration(ctx context.Context, id uint, calibration Calibration, recompute bool) (CalibrationChange, error)
+	DeleteDeviceCalibration(ctx context.Context, id uint, recompute bool) (CalibrationChange, error)
+
+	GetDeviceHealth(ctx context.Context, device string) (*models.DeviceHealth, error)
+	GetDevicesHealth(ctx context.Context, status *string) ([]models.DeviceHealth, error)
+	SetDeviceExpectedInterval(ctx context.Context, device string, interval *time.Duration) (*models.DeviceHealth, error)
+	UpdateDeviceStatuses(ctx context.Context, policy HealthPolicy) (HealthResult, error)
+
+	GetAlertRules(ctx context.Context) ([]models.AlertRule, error)
+	AddAlertRule(ctx context.Context, rule AlertRuleInput) (*models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, id uint, rule AlertRuleInput) (*models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id uint) (*models.AlertRule, error)
+
+	RelayOutbox(ctx context.Context, limit int, publish func(context.Context, OutboxMessage) error) (OutboxResult, error)
+
+	AddDeadLetter(ctx context.Context, letter NewDeadLetter) (*models.DeadLetter, error)
+	GetDeadLetters(ctx context.Context, query DeadLetterQuery) ([]models.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id uint64) (*models.DeadLetter, error)
+	RecordDeadLetterReplay(ctx context.Context, id uint64, replayErr error) (*models.DeadLetter, error)
+
+	ApplyRetention(ctx context.Context, policy RetentionPolicy) (RetentionResult, error)
+
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// AggregationInterval is the size of the time buckets used when computing statistics
+type AggregationInterval string
+
+const (
+	// AggregateHourly computes statistics per hour
+	AggregateHourly AggregationInterval = "hour"
+	// AggregateDaily computes statistics per day (UTC)
+	AggregateDaily AggregationInterval = "day"
+)
+
+// SortOrder controls the order in which measurements are returned from a history query
+type SortOrder int
+
+const (
+	// SortAscending returns the oldest measurements first
+	SortAscending SortOrder = iota
+	// SortDescending returns the most recent measurements first
+	SortDescending
+)
+
+// SnowdepthQuery describes a time bounded query for snow depth measurements. A zero
+// From or To leaves that end of the time span open, a nil Area includes all positions
+// and a zero Limit returns all matching measurements.
+type SnowdepthQuery struct {
+	Device *string
+	From   time.Time
+	To     time.Time
+	Area   *Area
+	Order  SortOrder
+	Limit  uint64
 }
 
 var dbCtxKey = &databaseContextKey{"database"}
@@ -54,7 +124,11 @@ func GetFromContext(ctx context.Context) (Datastore, error) {
 }
 
 type myDB struct {
-	impl *gorm.DB
+	impl            *gorm.DB
+	logMode         bool
+	timeouts        queryTimeouts
+	duplicatePolicy DuplicatePolicy
+	rules           ValidationRules
 }
 
 func getEnv(key, fallback string) string {
@@ -64,84 +138,446 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-// NewDatabaseConnection initializes a new connection to the database and wraps it in a Datastore
-func NewDatabaseConnection(logger zerolog.Logger) (Datastore, error) {
-	db := &myDB{}
+// NewDatastore creates the Datastore implementation selected by SNOWDEPTH_DB_TYPE,
+// which may be either "postgres" (the default) or "memory", using the duplicate policy
+// selected by SNOWDEPTH_DUPLICATE_POLICY and the validation rules from the environment
+func NewDatastore(logger zerolog.Logger) (Datastore, error) {
+	dbType := getEnv("SNOWDEPTH_DB_TYPE", "postgres")
 
-	dbHost := os.Getenv("SNOWDEPTH_DB_HOST")
-	username := os.Getenv("SNOWDEPTH_DB_USER")
-	dbName := os.Getenv("SNOWDEPTH_DB_NAME")
-	password := os.Getenv("SNOWDEPTH_DB_PASSWORD")
-	sslMode := getEnv("SNOWDEPTH_DB_SSLMODE", "require")
+	policy, err := loadDuplicatePolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := LoadValidationRules()
+	if err != nil {
+		return nil, err
+	}
 
-	dbURI := fmt.Sprintf("host=%s user=%s dbname=%s sslmode=%s password=%s", dbHost, username, dbName, sslMode, password)
+	switch dbType {
+	case "postgres":
+		return NewDatabaseConnection(logger, policy, rules)
+	case "memory":
+		return NewInMemoryDatastore(logger, policy, rules), nil
+	}
+
+	return nil, fmt.Errorf("unsupported database type %s", dbType)
+}
 
-	logger.Info().Str("host", dbHost).Msg("Connecting to database host ...")
-	conn, err := gorm.Open("postgres", dbURI)
+// NewDatabaseConnection initializes a new connection to the database and wraps it in a Datastore.
+// It refuses to use a database with pending migrations, unless SNOWDEPTH_DB_MIGRATE_ON_START
+// is set to true in which case the migrations are applied first.
+func NewDatabaseConnection(logger zerolog.Logger, policy DuplicatePolicy, rules ValidationRules) (Datastore, error) {
+	timeouts, err := loadQueryTimeouts()
 	if err != nil {
-		logger.Error().Err(err).Msg("failed to connect to database")
-		logger.Fatal().Msg("exiting")
+		return nil, err
 	}
-	defer conn.Close()
 
-	db.impl = conn.Debug()
-	logger.Info().Msg("executing migrations ...")
-	db.impl.AutoMigrate(&models.Snowdepth{})
+	conn, err := openConnection(logger)
+	if err != nil {
+		return nil, &Error{kind: ErrUnavailable, err: err}
+	}
+
+	db := &myDB{impl: conn.Debug(), logMode: true, timeouts: timeouts, duplicatePolicy: policy, rules: rules}
+
+	migrator, err := newMigrator(db.impl, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if pending > 0 {
+		if getEnv("SNOWDEPTH_DB_MIGRATE_ON_START", "false") != "true" {
+			db.Close()
+			return nil, fmt.Errorf(
+				"database has %d pending migration(s), run \"api-snowdepth migrate up\" or set SNOWDEPTH_DB_MIGRATE_ON_START=true",
+				pending,
+			)
+		}
+
+		logger.Info().Int("pending", pending).Msg("executing migrations ...")
+		if _, err = migrator.Up(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
 
 	logger.Info().Msg("done")
 
 	return db, nil
 }
 
-// AddManualSnowdepthMeasurement takes a position and a depth and adds a record to the database
-func (db *myDB) AddManualSnowdepthMeasurement(latitude, longitude, depth float64) (*models.Snowdepth, error) {
-	t := time.Now().UTC()
-	return db.AddSnowdepthMeasurement(nil, latitude, longitude, depth, t.Format(time.RFC3339))
+// Ping verifies that the database is still reachable
+func (db *myDB) Ping(ctx context.Context) error {
+	ctx, cancel := db.timeouts.withTimeout(ctx, opRead)
+	defer cancel()
+
+	if err := db.impl.DB().PingContext(ctx); err != nil {
+		return &Error{kind: ErrUnavailable, err: err}
+	}
+	return nil
+}
+
+// Close closes all connections in the pool
+func (db *myDB) Close() error {
+	return db.impl.Close()
 }
 
-// AddSnowdepthMeasurement takes a device, position and a depth and adds a record to the database
-func (db *myDB) AddSnowdepthMeasurement(device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, error) {
+// AddManualSnowdepthMeasurement takes a position and a depth and adds a record to the database,
+// together with an entry in the audit trail
+func (db *myDB) AddManualSnowdepthMeasurement(ctx context.Context, latitude, longitude, depth float64) (*models.Snowdepth, error) {
+	t := time.Now().UTC()
 
-	measurement := &models.Snowdepth{
-		Latitude:  latitude,
-		Longitude: longitude,
-		Depth:     float32(depth),
-		Timestamp: when,
+	measurement, err := newSnowdepth(NewMeasurement{nil, latitude, longitude, depth, t.Format(time.RFC3339Nano)})
+	if err != nil {
+		return nil, err
 	}
 
-	if device != nil {
-		measurement.Device = *device
+	var rejection *RejectedError
+
+	err = db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := db.calibrate(tx, []*models.Snowdepth{measurement}); err != nil {
+			return err
+		}
+
+		rejections, err := db.validate(tx, []*models.Snowdepth{measurement})
+		if err != nil {
+			return err
+		}
+
+		if rejection = rejections[0]; rejection != nil {
+			return nil
+		}
+
+		var previous *models.Snowdepth
+		measurement, previous, _, err = db.storeMeasurement(tx, measurement)
+		if err != nil {
+			return err
+		}
+
+		return appendAudit(tx, newAuditEntry(ctx, AuditAdd, "", previous, measurement))
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	db.impl.Create(measurement)
+	if rejection != nil {
+		return nil, rejection
+	}
 
 	return measurement, nil
 }
 
+// AddSnowdepthMeasurement takes a device, position and a depth and adds a record to the database.
+// The depth is computed with the calibration of the device, if there is one, and measurements
+// that fail a validation rule are quarantined and reported with a RejectedError. Either way the
+// device is recorded as seen.
+// If a measurement already exists for the device and timestamp, the duplicate policy decides
+// what happens and the returned measurement is the one that is stored after the operation.
+func (db *myDB) AddSnowdepthMeasurement(ctx context.Context, device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, IngestOutcome, error) {
+
+	measurement, err := newSnowdepth(NewMeasurement{device, latitude, longitude, depth, when})
+	if err != nil {
+		return nil, IngestInserted, err
+	}
+
+	outcome := IngestInserted
+	var rejection *RejectedError
+
+	err = db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := db.calibrate(tx, []*models.Snowdepth{measurement}); err != nil {
+			return err
+		}
+
+		rejections, err := db.validate(tx, []*models.Snowdepth{measurement})
+		if err != nil {
+			return err
+		}
+
+		if err = touchDevices(tx, []*models.Snowdepth{measurement}); err != nil {
+			return err
+		}
+
+		if rejection = rejections[0]; rejection != nil {
+			return nil
+		}
+
+		measurement, _, outcome, err = db.storeMeasurement(tx, measurement)
+		return err
+	})
+
+	if err != nil {
+		return nil, outcome, err
+	}
+
+	if rejection != nil {
+		return nil, IngestQuarantined, rejection
+	}
+
+	return measurement, outcome, nil
+}
+
+// storeMeasurement inserts a measurement, or applies the duplicate policy if a measurement
+// already exists for the device and timestamp. It returns the measurement that is stored after
+// the operation, as well as the values of the existing measurement if there was one. A
+// SnowdepthStored event, and the alerts that the measurement raises, are added to the outbox
+// if anything was stored.
+func (db *myDB) storeMeasurement(tx *gorm.DB, measurement *models.Snowdepth) (*models.Snowdepth, *models.Snowdepth, IngestOutcome, error) {
+	err := tx.Set("gorm:insert_option", "ON CONFLICT (device, timestamp) DO NOTHING").Create(measurement).Error
+	if err == nil {
+		if err = updateLatest(tx, measurement); err != nil {
+			return nil, nil, IngestInserted, err
+		}
+		if err = enqueueStored(tx, measurement); err != nil {
+			return nil, nil, IngestInserted, err
+		}
+		return measurement, nil, IngestInserted, raiseAlerts(tx, measurement)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, IngestInserted, err
+	}
+
+	// Nothing was inserted, so lock and compare with the measurement that is already stored
+	existing := &models.Snowdepth{}
+	err = tx.Unscoped().Set("gorm:query_option", "FOR UPDATE").
+		Where("device = ? AND timestamp = ?", measurement.Device, measurement.Timestamp).
+		First(existing).Error
+	if err != nil {
+		return nil, nil, IngestInserted, err
+	}
+
+	previous := *existing
+
+	outcome, overwrite, err := resolveDuplicate(db.duplicatePolicy, existing, measurement)
+	if err != nil {
+		return nil, nil, outcome, err
+	}
+
+	if overwrite {
+		err = tx.Unscoped().Model(existing).Updates(map[string]interface{}{
+			"latitude":       measurement.Latitude,
+			"longitude":      measurement.Longitude,
+			"depth":          measurement.Depth,
+			"raw_depth":      measurement.RawDepth,
+			"calibration_id": measurement.CalibrationID,
+		}).Error
+
+		if err == nil {
+			err = enqueueStored(tx, existing)
+		}
+
+		if err == nil {
+			err = raiseAlerts(tx, existing)
+		}
+	}
+
+	return existing, &previous, outcome, err
+}
+
+// latestSnowdepthsQuery returns the most recent measurement from each device, as well as the
+// manually added measurements, in a single statement so that both come from the same snapshot
+const latestSnowdepthsQuery = `
+	SELECT * FROM (
+		SELECT snowdepths.* FROM latest_snowdepths
+		JOIN snowdepths ON snowdepths.id = latest_snowdepths.snowdepth_id
+		WHERE latest_snowdepths.timestamp > ? AND snowdepths.deleted_at IS NULL
+		UNION ALL
+		SELECT * FROM snowdepths
+		WHERE device = '' AND timestamp > ? AND deleted_at IS NULL
+	) AS latest
+	ORDER BY device = '', device, timestamp`
+
 // GetLatestSnowdepths returns the most recent value for all sensors, as well as
 // all manually added values during the last 24 hours
-func (db *myDB) GetLatestSnowdepths() ([]models.Snowdepth, error) {
+func (db *myDB) GetLatestSnowdepths(ctx context.Context) ([]models.Snowdepth, error) {
 
 	// Get depths from the last 24 hours
-	queryStart := time.Now().UTC().AddDate(0, 0, -1).Format(time.RFC3339)
+	queryStart := time.Now().UTC().AddDate(0, 0, -1)
 
-	// TODO: Implement this as a single operation instead
+	depths := []models.Snowdepth{}
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.Raw(latestSnowdepthsQuery, queryStart, queryStart).Scan(&depths).Error
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	latestFromDevices := []models.Snowdepth{}
-	db.impl.Table("snowdepths").Select("DISTINCT ON (device) *").Where("device <> '' AND timestamp > ?", queryStart).Order("device, timestamp desc").Find(&latestFromDevices)
+	return depths, nil
+}
 
-	latestManual := []models.Snowdepth{}
-	db.impl.Table("snowdepths").Where("device = '' AND timestamp > ?", queryStart).Find(&latestManual)
+// updateLatest makes newly inserted measurements the latest ones for their devices, unless
+// more recent measurements have already been stored
+func updateLatest(tx *gorm.DB, measurements ...*models.Snowdepth) error {
+	// A device may only occur once in the statement, so pick the most recent measurement per device
+	latest := map[string]*models.Snowdepth{}
+	devices := []string{}
+
+	for _, m := range measurements {
+		if m.Device == "" {
+			continue
+		}
+
+		current, ok := latest[m.Device]
+		if !ok {
+			devices = append(devices, m.Device)
+		}
+		if !ok || m.Timestamp.After(current.Timestamp) {
+			latest[m.Device] = m
+		}
+	}
 
-	return append(latestFromDevices, latestManual...), nil
+	if len(devices) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(devices))
+	args := make([]interface{}, 0, len(devices)*3)
+
+	for _, device := range devices {
+		m := latest[device]
+		values = append(values, "(?, ?, ?)")
+		args = append(args, m.Device, m.ID, m.Timestamp)
+	}
+
+	return tx.Exec(`
+		INSERT INTO latest_snowdepths (device, snowdepth_id, timestamp) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (device) DO UPDATE SET snowdepth_id = excluded.snowdepth_id, timestamp = excluded.timestamp
+		WHERE latest_snowdepths.timestamp < excluded.timestamp`,
+		args...,
+	).Error
 }
 
-func (db *myDB) GetLatestSnowdepthsForDevice(device string) ([]models.Snowdepth, error) {
+func (db *myDB) GetLatestSnowdepthsForDevice(ctx context.Context, device string) ([]models.Snowdepth, error) {
 	// Get depths from the last 24 hours
-	queryStart := time.Now().UTC().AddDate(0, 0, -1).Format(time.RFC3339)
+	queryStart := time.Now().UTC().AddDate(0, 0, -1)
+
+	depths := []models.Snowdepth{}
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.Table("snowdepths").Where("device = ? AND timestamp > ?", device, queryStart).Find(&depths).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return depths, nil
+}
+
+// GetSnowdepthHistory returns the measurements that match the query, where From is
+// inclusive and To is exclusive. If no device is specified, measurements from all
+// devices as well as the manually added ones are returned. Measurements that have been
+// downsampled are returned as the mean depth at the start of each hour.
+func (db *myDB) GetSnowdepthHistory(ctx context.Context, query SnowdepthQuery) ([]models.Snowdepth, error) {
+	if query.Area != nil {
+		if err := query.Area.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var depths, aggregated []models.Snowdepth
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		var err error
+		if depths, err = getRawHistory(tx, query); err != nil {
+			return err
+		}
+		aggregated, err = getAggregatedHistory(tx, query)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeHistory(depths, aggregated, query.Order, query.Limit), nil
+}
+
+func getRawHistory(tx *gorm.DB, query SnowdepthQuery) ([]models.Snowdepth, error) {
+	tx = tx.Table("snowdepths")
+
+	if query.Device != nil {
+		tx = tx.Where("device = ?", *query.Device)
+	}
+
+	if !query.From.IsZero() {
+		tx = tx.Where("timestamp >= ?", query.From)
+	}
+
+	if !query.To.IsZero() {
+		tx = tx.Where("timestamp < ?", query.To)
+	}
+
+	if query.Area != nil {
+		tx = whereWithin(tx, *query.Area)
+	}
+
+	if query.Order == SortDescending {
+		tx = tx.Order("timestamp desc")
+	} else {
+		tx = tx.Order("timestamp asc")
+	}
+
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
 
 	depths := []models.Snowdepth{}
-	db.impl.Table("snowdepths").Where("device = ? AND timestamp > ?", device, queryStart).Find(&depths)
+	if err := tx.Find(&depths).Error; err != nil {
+		return nil, err
+	}
 
 	return depths, nil
 }
+
+// GetSnowdepthStatistics returns the min, max, mean, count and last value per device and
+// interval for all measurements within the time span from (inclusive) to (exclusive),
+// including those that have been downsampled
+func (db *myDB) GetSnowdepthStatistics(ctx context.Context, device *string, from, to time.Time, interval AggregationInterval) ([]models.SnowdepthStatistics, error) {
+	if interval != AggregateHourly && interval != AggregateDaily {
+		return nil, newError(ErrValidation, "unsupported aggregation interval %s", interval)
+	}
+
+	var stats, aggregated []models.SnowdepthStatistics
+
+	err := db.read(ctx, opStatistics, func(tx *gorm.DB) error {
+		var err error
+		if stats, err = getRawStatistics(tx, device, from, to, interval); err != nil {
+			return err
+		}
+		aggregated, err = getAggregatedStatistics(tx, device, from, to, interval)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeStatistics(stats, aggregated), nil
+}
+
+func getRawStatistics(tx *gorm.DB, device *string, from, to time.Time, interval AggregationInterval) ([]models.SnowdepthStatistics, error) {
+	tx = tx.Table("snowdepths").
+		Select(
+			"device, date_trunc(?, timestamp AT TIME ZONE 'UTC') AS start, "+
+				"min(depth) AS min, max(depth) AS max, avg(depth) AS mean, count(*) AS count, "+
+				"(array_agg(depth ORDER BY timestamp DESC))[1] AS last",
+			string(interval),
+		).
+		Where("deleted_at IS NULL AND timestamp >= ? AND timestamp < ?", from, to)
+
+	if device != nil {
+		tx = tx.Where("device = ?", *device)
+	}
+
+	stats := []models.SnowdepthStatistics{}
+	if err := tx.Group("device, start").Order("device, start").Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
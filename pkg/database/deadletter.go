@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+	"github.com/diwise/messaging-golang/pkg/messaging/telemetry"
+)
+
+// Reasons why a received message is dead-lettered
+const (
+	// DeadLetterMalformed means that the message could not be parsed as snowdepth telemetry
+	DeadLetterMalformed = "malformed"
+	// DeadLetterRejected means that the measurement was invalid, for example because of a bad timestamp
+	DeadLetterRejected = "rejected"
+	// DeadLetterRetriesExhausted means that storing the measurement kept failing for a transient reason
+	DeadLetterRetriesExhausted = "retries-exhausted"
+)
+
+// NewDeadLetter describes a received message that could not be stored
+type NewDeadLetter struct {
+	RoutingKey  string
+	ContentType string
+	Body        []byte
+	Reason      string
+	Err         error
+	Attempts    int
+}
+
+func (d NewDeadLetter) validate() error {
+	switch d.Reason {
+	case DeadLetterMalformed, DeadLetterRejected, DeadLetterRetriesExhausted:
+		return nil
+	}
+
+	return newError(ErrValidation, "unsupported dead letter reason %q", d.Reason)
+}
+
+func newDeadLetter(d NewDeadLetter) *models.DeadLetter {
+	letter := &models.DeadLetter{
+		CreatedAt:   time.Now().UTC(),
+		RoutingKey:  d.RoutingKey,
+		ContentType: d.ContentType,
+		Body:        string(d.Body),
+		Reason:      d.Reason,
+		Attempts:    d.Attempts,
+	}
+
+	if d.Err != nil {
+		letter.Error = d.Err.Error()
+	}
+
+	return letter
+}
+
+// DeadLetterQuery selects dead letters by the time they were added. Zero values do not
+// restrict the result, and the most recent dead letters are returned first.
+type DeadLetterQuery struct {
+	From     time.Time
+	To       time.Time
+	Reason   *string
+	Replayed *bool
+	Limit    uint64
+}
+
+func (q DeadLetterQuery) matches(d models.DeadLetter) bool {
+	if !q.From.IsZero() && d.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !d.CreatedAt.Before(q.To) {
+		return false
+	}
+	if q.Reason != nil && d.Reason != *q.Reason {
+		return false
+	}
+	if q.Replayed != nil && (d.ReplayedAt != nil) != *q.Replayed {
+		return false
+	}
+	return true
+}
+
+// ParseTelemetry parses a received snowdepth telemetry message
+func ParseTelemetry(body []byte) (NewMeasurement, error) {
+	depth := &telemetry.Snowdepth{}
+	if err := json.Unmarshal(body, depth); err != nil {
+		return NewMeasurement{}, newError(ErrValidation, "malformed snowdepth telemetry: %s", err.Error())
+	}
+
+	return NewMeasurement{
+		Device:    &depth.Origin.Device,
+		Latitude:  depth.Origin.Latitude,
+		Longitude: depth.Origin.Longitude,
+		Depth:     float64(depth.Depth),
+		When:      depth.Timestamp,
+	}, nil
+}
+
+// IsHandled reports whether a received measurement has been dealt with, so that it should
+// not be retried or dead-lettered. Duplicates and conflicts are handled by the duplicate
+// policy, and quarantined measurements have been stored for review.
+func IsHandled(outcome IngestOutcome, err error) bool {
+	return err == nil ||
+		outcome == IngestQuarantined ||
+		errors.Is(err, ErrDuplicateMeasurement) ||
+		errors.Is(err, ErrConflictingMeasurement)
+}
+
+// DeadLetterReplay describes the result of replaying a dead letter. Err is set if the
+// measurement still could not be stored, in which case the dead letter is kept as it was.
+type DeadLetterReplay struct {
+	DeadLetter  *models.DeadLetter
+	Measurement *models.Snowdepth
+	Outcome     IngestOutcome
+	Err         error
+}
+
+// ReplayDeadLetter parses the body of a dead letter again and adds the measurement through
+// db, so that it goes through the same validation, duplicate policy and alert rules as a
+// received message. The dead letter is marked as replayed if the measurement was handled.
+func ReplayDeadLetter(ctx context.Context, db Datastore, id uint64) (DeadLetterReplay, error) {
+	letter, err := db.GetDeadLetter(ctx, id)
+	if err != nil {
+		return DeadLetterReplay{}, err
+	}
+
+	replay := DeadLetterReplay{}
+
+	m, err := ParseTelemetry([]byte(letter.Body))
+	if err == nil {
+		replay.Measurement, replay.Outcome, err = db.AddSnowdepthMeasurement(ctx, m.Device, m.Latitude, m.Longitude, m.Depth, m.When)
+	}
+
+	if !IsHandled(replay.Outcome, err) {
+		if IsRetryable(err) {
+			return DeadLetterReplay{}, err
+		}
+		replay.Err = err
+	}
+
+	replay.DeadLetter, err = db.RecordDeadLetterReplay(ctx, id, replay.Err)
+	if err != nil {
+		return DeadLetterReplay{}, err
+	}
+
+	return replay, nil
+}
+
+// replayedChanges returns the changes to a dead letter after it has been replayed
+func replayedChanges(replayErr error, now time.Time) map[string]interface{} {
+	changes := map[string]interface{}{"attempts": gorm.Expr("attempts + 1")}
+
+	if replayErr != nil {
+		changes["error"] = replayErr.Error()
+	} else {
+		changes["replayed_at"] = now
+	}
+
+	return changes
+}
+
+// AddDeadLetter stores a received message that could not be stored
+func (db *myDB) AddDeadLetter(ctx context.Context, d NewDeadLetter) (*models.DeadLetter, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	letter := newDeadLetter(d)
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		return tx.Create(letter).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return letter, nil
+}
+
+// GetDeadLetters returns the dead letters that match the query
+func (db *myDB) GetDeadLetters(ctx context.Context, query DeadLetterQuery) ([]models.DeadLetter, error) {
+	letters := []models.DeadLetter{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		if !query.From.IsZero() {
+			tx = tx.Where("created_at >= ?", query.From)
+		}
+		if !query.To.IsZero() {
+			tx = tx.Where("created_at < ?", query.To)
+		}
+		if query.Reason != nil {
+			tx = tx.Where("reason = ?", *query.Reason)
+		}
+		if query.Replayed != nil {
+			if *query.Replayed {
+				tx = tx.Where("replayed_at IS NOT NULL")
+			} else {
+				tx = tx.Where("replayed_at IS NULL")
+			}
+		}
+		if query.Limit > 0 {
+			tx = tx.Limit(query.Limit)
+		}
+
+		return tx.Order("id desc").Find(&letters).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+// GetDeadLetter returns a single dead letter
+func (db *myDB) GetDeadLetter(ctx context.Context, id uint64) (*models.DeadLetter, error) {
+	letter := &models.DeadLetter{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.First(letter, id).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return letter, nil
+}
+
+// RecordDeadLetterReplay counts an attempt to replay a dead letter, and marks it as replayed
+// unless the attempt failed with replayErr
+func (db *myDB) RecordDeadLetterReplay(ctx context.Context, id uint64, replayErr error) (*models.DeadLetter, error) {
+	letter := &models.DeadLetter{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").First(letter, id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(letter).Updates(replayedChanges(replayErr, time.Now().UTC())).Error; err != nil {
+			return err
+		}
+
+		return tx.First(letter, id).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return letter, nil
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrDuplicateMeasurement is returned when a measurement from the same device and
+	// with the same timestamp has already been stored
+	ErrDuplicateMeasurement = errors.New("duplicate measurement")
+	// ErrConflictingMeasurement is returned when a measurement with different values has
+	// already been stored for the same device and timestamp
+	ErrConflictingMeasurement = errors.New("conflicting measurement")
+	// ErrValidation is returned when a measurement or a query contains invalid data
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable is returned when the database can not be reached. Operations that
+	// fail with this error may succeed if they are retried later.
+	ErrUnavailable = errors.New("database unavailable")
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = errors.New("not found")
+)
+
+// Error wraps an underlying error together with one of the error kinds above, so that
+// callers can use errors.Is to decide how to handle it
+type Error struct {
+	kind error
+	err  error
+}
+
+func (e *Error) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error is of the target kind
+func (e *Error) Is(target error) bool {
+	return target == e.kind
+}
+
+func newError(kind error, format string, args ...interface{}) error {
+	return &Error{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// IsRetryable reports whether an operation that failed with err may succeed if retried
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// InvalidTimestampError is returned when the timestamp of a measurement can not be parsed
+type InvalidTimestampError struct {
+	Timestamp string
+	Err       error
+}
+
+func (e *InvalidTimestampError) Error() string {
+	return fmt.Sprintf("invalid measurement timestamp %q: %s", e.Timestamp, e.Err.Error())
+}
+
+func (e *InvalidTimestampError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes an InvalidTimestampError match ErrValidation
+func (e *InvalidTimestampError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// parseTimestamp parses an RFC3339 timestamp, with or without fractional seconds and
+// with any timezone offset, and normalises it to UTC with the microsecond precision
+// used by the timestamptz column
+func parseTimestamp(when string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, when)
+	if err != nil {
+		return time.Time{}, &InvalidTimestampError{Timestamp: when, Err: err}
+	}
+
+	return t.UTC().Truncate(time.Microsecond), nil
+}
+
+// classifyError wraps errors returned from gorm and the postgres driver in an Error
+// of the matching kind. Unknown errors are returned unchanged.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var dbErr *Error
+	if errors.As(err, &dbErr) {
+		return err
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return &Error{kind: ErrNotFound, err: err}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code.Name() == "unique_violation":
+			return &Error{kind: ErrDuplicateMeasurement, err: err}
+		case pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23":
+			// data exceptions and other integrity constraint violations
+			return &Error{kind: ErrValidation, err: err}
+		case pqErr.Code.Class() == "08" || pqErr.Code.Class() == "53" || pqErr.Code.Class() == "57":
+			// connection exceptions, insufficient resources and operator intervention
+			return &Error{kind: ErrUnavailable, err: err}
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return &Error{kind: ErrUnavailable, err: err}
+	}
+
+	return err
+}
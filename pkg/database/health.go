@@ -0,0 +1,309 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// Statuses that a device can have, depending on how long ago it last reported
+const (
+	DeviceOnline  = "online"
+	DeviceStale   = "stale"
+	DeviceOffline = "offline"
+)
+
+// healthLockID is an arbitrary key for the advisory lock that makes sure only one instance
+// updates the device statuses at a time
+const healthLockID = 7382197
+
+// HealthPolicy decides when devices are considered stale or offline. A device is stale when
+// it has not reported for StaleFactor times its expected interval, and offline after
+// OfflineFactor times the interval.
+type HealthPolicy struct {
+	// DefaultInterval is the expected interval of devices that have not been given one
+	DefaultInterval time.Duration
+	StaleFactor     float64
+	OfflineFactor   float64
+	// CheckInterval is how often the statuses are updated
+	CheckInterval time.Duration
+}
+
+// LoadHealthPolicy reads the health policy from SNOWDEPTH_HEALTH_DEFAULT_INTERVAL,
+// SNOWDEPTH_HEALTH_STALE_FACTOR, SNOWDEPTH_HEALTH_OFFLINE_FACTOR and
+// SNOWDEPTH_HEALTH_CHECK_INTERVAL
+func LoadHealthPolicy() (HealthPolicy, error) {
+	policy := HealthPolicy{}
+	var err error
+
+	if policy.DefaultInterval, err = getEnvDuration("SNOWDEPTH_HEALTH_DEFAULT_INTERVAL", time.Hour); err != nil {
+		return policy, err
+	}
+
+	if policy.DefaultInterval < time.Second {
+		return policy, fmt.Errorf("SNOWDEPTH_HEALTH_DEFAULT_INTERVAL must be at least a second")
+	}
+
+	if policy.StaleFactor, err = getEnvFloat("SNOWDEPTH_HEALTH_STALE_FACTOR", 2); err != nil {
+		return policy, err
+	}
+
+	if policy.StaleFactor < 1 {
+		return policy, fmt.Errorf("SNOWDEPTH_HEALTH_STALE_FACTOR must be at least 1")
+	}
+
+	if policy.OfflineFactor, err = getEnvFloat("SNOWDEPTH_HEALTH_OFFLINE_FACTOR", 6); err != nil {
+		return policy, err
+	}
+
+	if policy.OfflineFactor < policy.StaleFactor {
+		return policy, fmt.Errorf("SNOWDEPTH_HEALTH_OFFLINE_FACTOR must not be less than SNOWDEPTH_HEALTH_STALE_FACTOR")
+	}
+
+	if policy.CheckInterval, err = getEnvDuration("SNOWDEPTH_HEALTH_CHECK_INTERVAL", time.Minute); err != nil {
+		return policy, err
+	}
+
+	if policy.CheckInterval <= 0 {
+		return policy, fmt.Errorf("SNOWDEPTH_HEALTH_CHECK_INTERVAL must be positive")
+	}
+
+	return policy, nil
+}
+
+// ExpectedInterval returns the interval between reports that is expected from a device
+func (p HealthPolicy) ExpectedInterval(health models.DeviceHealth) time.Duration {
+	if health.ExpectedInterval != nil {
+		return time.Duration(*health.ExpectedInterval) * time.Second
+	}
+	return p.DefaultInterval
+}
+
+// status returns the status that a device should have at the time now
+func (p HealthPolicy) status(health models.DeviceHealth, now time.Time) string {
+	silence := now.Sub(health.LastSeen)
+	interval := float64(p.ExpectedInterval(health))
+
+	if float64(silence) >= interval*p.OfflineFactor {
+		return DeviceOffline
+	} else if float64(silence) >= interval*p.StaleFactor {
+		return DeviceStale
+	}
+
+	return DeviceOnline
+}
+
+// DeviceStatusChange reports that the status of a device has changed
+type DeviceStatusChange struct {
+	Device string
+	// Previous is the status before the change, which is empty if it had not been determined
+	Previous         string
+	Status           string
+	LastSeen         time.Time
+	ExpectedInterval time.Duration
+	ChangedAt        time.Time
+}
+
+// HealthResult reports what happened when the device statuses were updated
+type HealthResult struct {
+	// Skipped is set when another instance was already updating the statuses
+	Skipped bool
+	Changes []DeviceStatusChange
+}
+
+// updateStatuses applies the policy to the devices, changes the status of those that need
+// it, and returns the changes
+func (p HealthPolicy) updateStatuses(devices []models.DeviceHealth, now time.Time) []DeviceStatusChange {
+	changes := []DeviceStatusChange{}
+
+	for idx := range devices {
+		health := &devices[idx]
+		status := p.status(*health, now)
+
+		if status == health.Status {
+			continue
+		}
+
+		changes = append(changes, DeviceStatusChange{
+			Device:           health.Device,
+			Previous:         health.Status,
+			Status:           status,
+			LastSeen:         health.LastSeen,
+			ExpectedInterval: p.ExpectedInterval(*health),
+			ChangedAt:        now,
+		})
+
+		health.Status = status
+		health.StatusChangedAt = now
+	}
+
+	return changes
+}
+
+// lastSeen returns the most recent timestamp of the measurements from each device. Future
+// timestamps are limited to now, so that a device with a skewed clock is not reported as
+// seen long after it has stopped reporting.
+func lastSeen(measurements []*models.Snowdepth, now time.Time) ([]string, map[string]time.Time) {
+	devices := []string{}
+	seen := map[string]time.Time{}
+
+	for _, m := range measurements {
+		if m == nil || m.Device == "" {
+			continue
+		}
+
+		t := m.Timestamp
+		if t.After(now) {
+			t = now
+		}
+
+		current, ok := seen[m.Device]
+		if !ok {
+			devices = append(devices, m.Device)
+		}
+		if !ok || t.After(current) {
+			seen[m.Device] = t
+		}
+	}
+
+	return devices, seen
+}
+
+// touchDevices records that measurements have been received from their devices, including
+// measurements that are quarantined, since they show that the device is still reporting.
+// New devices are online from their first report.
+func touchDevices(tx *gorm.DB, measurements []*models.Snowdepth) error {
+	now := time.Now().UTC()
+	devices, seen := lastSeen(measurements, now)
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(devices))
+	args := make([]interface{}, 0, len(devices)*4)
+
+	for _, device := range devices {
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, device, seen[device], DeviceOnline, now)
+	}
+
+	return tx.Exec(`
+		INSERT INTO device_health (device, last_seen, status, status_changed_at) VALUES `+strings.Join(values, ", ")+`
+		ON CONFLICT (device) DO UPDATE SET last_seen = greatest(device_health.last_seen, excluded.last_seen)`,
+		args...,
+	).Error
+}
+
+// GetDeviceHealth returns the health of a device, or fails with ErrNotFound if the device
+// has never reported a measurement
+func (db *myDB) GetDeviceHealth(ctx context.Context, device string) (*models.DeviceHealth, error) {
+	health := &models.DeviceHealth{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		return tx.Where("device = ?", device).First(health).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return health, nil
+}
+
+// GetDevicesHealth returns the health of all devices ordered by device, optionally limited to
+// the devices that have a certain status
+func (db *myDB) GetDevicesHealth(ctx context.Context, status *string) ([]models.DeviceHealth, error) {
+	devices := []models.DeviceHealth{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		if status != nil {
+			tx = tx.Where("status = ?", *status)
+		}
+		return tx.Order("device").Find(&devices).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// SetDeviceExpectedInterval changes how often a device is expected to report. A nil interval
+// makes the device use the default interval again. The new interval is taken into account
+// the next time that the statuses are updated.
+func (db *myDB) SetDeviceExpectedInterval(ctx context.Context, device string, interval *time.Duration) (*models.DeviceHealth, error) {
+	var seconds *int64
+
+	if interval != nil {
+		if *interval < time.Second {
+			return nil, newError(ErrValidation, "the expected interval of a device must be at least a second")
+		}
+		s := int64(*interval / time.Second)
+		seconds = &s
+	}
+
+	health := &models.DeviceHealth{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Where("device = ?", device).First(health).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(health).Update("expected_interval", seconds).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return health, nil
+}
+
+// UpdateDeviceStatuses applies the health policy to all devices and returns the devices that
+// changed status. The update is skipped if another instance is already doing it.
+func (db *myDB) UpdateDeviceStatuses(ctx context.Context, policy HealthPolicy) (HealthResult, error) {
+	result := HealthResult{}
+	now := time.Now().UTC()
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		locked := false
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", healthLockID).Row().Scan(&locked); err != nil {
+			return err
+		}
+
+		if !locked {
+			result.Skipped = true
+			return nil
+		}
+
+		devices := []models.DeviceHealth{}
+		if err := tx.Set("gorm:query_option", "FOR UPDATE").Order("device").Find(&devices).Error; err != nil {
+			return err
+		}
+
+		result.Changes = policy.updateStatuses(devices, now)
+
+		for _, change := range result.Changes {
+			err := tx.Model(&models.DeviceHealth{}).Where("device = ?", change.Device).
+				Updates(map[string]interface{}{"status": change.Status, "status_changed_at": change.ChangedAt}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return HealthResult{}, err
+	}
+
+	return result, nil
+}
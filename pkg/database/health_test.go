@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+var testHealthPolicy = HealthPolicy{DefaultInterval: time.Hour, StaleFactor: 2, OfflineFactor: 6, CheckInterval: time.Minute}
+
+func TestHealthPolicyStatus(t *testing.T) {
+	now := testStart.Add(24 * time.Hour)
+	thirtyMinutes := int64(1800)
+
+	tests := []struct {
+		name     string
+		silence  time.Duration
+		interval *int64
+		expected string
+	}{
+		{"reported recently", 30 * time.Minute, nil, DeviceOnline},
+		{"just before stale", 2*time.Hour - time.Second, nil, DeviceOnline},
+		{"stale", 2 * time.Hour, nil, DeviceStale},
+		{"just before offline", 6*time.Hour - time.Second, nil, DeviceStale},
+		{"offline", 6 * time.Hour, nil, DeviceOffline},
+		{"stale with a shorter interval", time.Hour, &thirtyMinutes, DeviceStale},
+		{"offline with a shorter interval", 3 * time.Hour, &thirtyMinutes, DeviceOffline},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			health := models.DeviceHealth{Device: "a", LastSeen: now.Add(-tc.silence), ExpectedInterval: tc.interval}
+			if status := testHealthPolicy.status(health, now); status != tc.expected {
+				t.Errorf("expected status %s, got %s", tc.expected, status)
+			}
+		})
+	}
+}
+
+func TestHealthPolicyUpdateStatuses(t *testing.T) {
+	now := testStart.Add(24 * time.Hour)
+
+	devices := []models.DeviceHealth{
+		{Device: "a", LastSeen: now.Add(-time.Minute), Status: DeviceOnline},
+		{Device: "b", LastSeen: now.Add(-3 * time.Hour), Status: DeviceOnline},
+		{Device: "c", LastSeen: now.Add(-time.Minute), Status: DeviceOffline},
+	}
+
+	changes := testHealthPolicy.updateStatuses(devices, now)
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Device != "b" || changes[0].Previous != DeviceOnline || changes[0].Status != DeviceStale {
+		t.Errorf("expected b to become stale, got %+v", changes[0])
+	}
+	if changes[1].Device != "c" || changes[1].Previous != DeviceOffline || changes[1].Status != DeviceOnline {
+		t.Errorf("expected c to come back online, got %+v", changes[1])
+	}
+	if devices[1].Status != DeviceStale || !devices[1].StatusChangedAt.Equal(now) || !devices[0].StatusChangedAt.IsZero() {
+		t.Errorf("expected only the changed devices to be updated, got %+v", devices)
+	}
+}
+
+func TestLastSeenIsLimitedToNow(t *testing.T) {
+	now := testStart.Add(time.Hour)
+
+	devices, seen := lastSeen([]*models.Snowdepth{
+		{Device: "a", Timestamp: testStart},
+		{Device: "b", Timestamp: now.Add(time.Hour)},
+		{Device: "a", Timestamp: testStart.Add(30 * time.Minute)},
+		{Timestamp: testStart},
+		nil,
+	}, now)
+
+	if len(devices) != 2 || devices[0] != "a" || devices[1] != "b" {
+		t.Fatalf("expected devices a and b, got %v", devices)
+	}
+	if !seen["a"].Equal(testStart.Add(30 * time.Minute)) {
+		t.Errorf("expected a to be seen at its latest measurement, got %s", seen["a"])
+	}
+	if !seen["b"].Equal(now) {
+		t.Errorf("expected a measurement from the future to count as now, got %s", seen["b"])
+	}
+}
+
+func TestInMemoryDeviceHealth(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	if _, err := db.GetDeviceHealth(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown device not to be found, got %v", err)
+	}
+
+	addMeasurement(t, db, "a", 0, 10)
+	addMeasurement(t, db, "b", 0, 10)
+
+	health, err := db.GetDeviceHealth(ctx, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if health.Status != DeviceOnline || !health.LastSeen.Equal(testStart) {
+		t.Errorf("expected a new device to be online and seen at its measurement, got %+v", health)
+	}
+
+	// The measurements are from long ago, unless a device is only expected to report once a decade
+	decade := 24 * 365 * 10 * time.Hour
+	if _, err := db.SetDeviceExpectedInterval(ctx, "b", &decade); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := db.UpdateDeviceStatuses(ctx, testHealthPolicy)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Device != "a" || result.Changes[0].Status != DeviceOffline {
+		t.Errorf("expected only a to go offline, got %+v", result.Changes)
+	}
+
+	offline := DeviceOffline
+	devices, err := db.GetDevicesHealth(ctx, &offline)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(devices) != 1 || devices[0].Device != "a" {
+		t.Errorf("expected only a to be offline, got %+v", devices)
+	}
+
+	if _, err := db.SetDeviceExpectedInterval(ctx, "b", nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result, _ := db.UpdateDeviceStatuses(ctx, testHealthPolicy); len(result.Changes) != 1 || result.Changes[0].Device != "b" {
+		t.Errorf("expected b to go offline with the default interval, got %+v", result.Changes)
+	}
+
+	tooShort := time.Millisecond
+	if _, err := db.SetDeviceExpectedInterval(ctx, "a", &tooShort); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected an interval below a second to be refused, got %v", err)
+	}
+	if _, err := db.SetDeviceExpectedInterval(ctx, "c", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown device not to be found, got %v", err)
+	}
+}
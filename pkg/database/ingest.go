@@ -0,0 +1,143 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// DuplicatePolicy decides what happens when a measurement is added for a device and
+// timestamp that already has a stored measurement
+type DuplicatePolicy string
+
+const (
+	// DuplicateIgnore keeps the stored measurement and ignores the new one
+	DuplicateIgnore DuplicatePolicy = "ignore"
+	// DuplicateOverwrite replaces the position and depth of the stored measurement
+	DuplicateOverwrite DuplicatePolicy = "overwrite"
+	// DuplicateRejectIfDifferent ignores identical measurements, but fails with
+	// ErrConflictingMeasurement if the new measurement differs from the stored one
+	DuplicateRejectIfDifferent DuplicatePolicy = "reject-if-different"
+)
+
+// IngestOutcome describes what happened to a measurement that was added to the datastore
+type IngestOutcome int
+
+const (
+	// IngestInserted means that the measurement was stored as a new record
+	IngestInserted IngestOutcome = iota
+	// IngestDuplicate means that an identical measurement had already been stored
+	IngestDuplicate
+	// IngestConflictIgnored means that a different measurement had already been stored
+	// and was kept according to the duplicate policy
+	IngestConflictIgnored
+	// IngestOverwritten means that a different measurement had already been stored
+	// and was overwritten according to the duplicate policy
+	IngestOverwritten
+	// IngestQuarantined means that the measurement failed a validation rule and was stored
+	// in the quarantine instead
+	IngestQuarantined
+)
+
+func (o IngestOutcome) String() string {
+	switch o {
+	case IngestInserted:
+		return "inserted"
+	case IngestDuplicate:
+		return "duplicate"
+	case IngestConflictIgnored:
+		return "conflict-ignored"
+	case IngestOverwritten:
+		return "overwritten"
+	case IngestQuarantined:
+		return "quarantined"
+	}
+	return "unknown"
+}
+
+// NewMeasurement is a measurement to be added with AddSnowdepthMeasurements. A nil Device
+// means that the measurement was added manually.
+type NewMeasurement struct {
+	Device    *string
+	Latitude  float64
+	Longitude float64
+	Depth     float64
+	When      string
+}
+
+// IngestResult describes what happened to a single measurement in a batch. Measurement is
+// the stored measurement and is nil if Err is set. Quarantined measurements are reported
+// with a RejectedError.
+type IngestResult struct {
+	Measurement *models.Snowdepth
+	Outcome     IngestOutcome
+	Err         error
+}
+
+// MaxBatchSize is the largest number of measurements that may be added in a single batch
+const MaxBatchSize = 1000
+
+func newSnowdepth(m NewMeasurement) (*models.Snowdepth, error) {
+	timestamp, err := parseTimestamp(m.When)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement := &models.Snowdepth{
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Depth:     float32(m.Depth),
+		RawDepth:  float32(m.Depth),
+		Timestamp: timestamp,
+	}
+
+	if m.Device != nil {
+		measurement.Device = *m.Device
+	}
+
+	return measurement, nil
+}
+
+// loadDuplicatePolicy reads the duplicate policy from SNOWDEPTH_DUPLICATE_POLICY
+func loadDuplicatePolicy() (DuplicatePolicy, error) {
+	policy := DuplicatePolicy(getEnv("SNOWDEPTH_DUPLICATE_POLICY", string(DuplicateIgnore)))
+
+	switch policy {
+	case DuplicateIgnore, DuplicateOverwrite, DuplicateRejectIfDifferent:
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("unsupported duplicate policy %s", policy)
+}
+
+func hasSameValues(a, b *models.Snowdepth) bool {
+	return a.Latitude == b.Latitude && a.Longitude == b.Longitude && a.Depth == b.Depth
+}
+
+// resolveDuplicate applies the duplicate policy to a new measurement that collides with an
+// existing one. It returns the outcome and whether the existing record should be overwritten.
+// A measurement that has been corrected or retracted is never overwritten, so that a redelivered
+// message can not undo the change.
+func resolveDuplicate(policy DuplicatePolicy, existing, measurement *models.Snowdepth) (IngestOutcome, bool, error) {
+	if hasSameValues(existing, measurement) {
+		return IngestDuplicate, false, nil
+	}
+
+	if existing.DeletedAt != nil || existing.ChangedBy != "" {
+		return IngestConflictIgnored, false, nil
+	}
+
+	switch policy {
+	case DuplicateOverwrite:
+		return IngestOverwritten, true, nil
+	case DuplicateRejectIfDifferent:
+		return IngestConflictIgnored, false, newError(
+			ErrConflictingMeasurement,
+			"a different measurement from device %q at %s has already been stored (depth %.1f, new depth %.1f)",
+			existing.Device, existing.Timestamp.Format(time.RFC3339Nano), existing.Depth, measurement.Depth,
+		)
+	}
+
+	return IngestConflictIgnored, false, nil
+}
@@ -0,0 +1,1245 @@
+package database
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+type inMemoryDB struct {
+	mu              sync.RWMutex
+	relayMu         sync.Mutex
+	depths          []models.Snowdepth
+	aggregates      []models.SnowdepthAggregate
+	audit           []models.SnowdepthAuditEntry
+	quarantine      []models.QuarantinedSnowdepth
+	calibrations    []models.DeviceCalibration
+	health          map[string]*models.DeviceHealth
+	alertRules      []models.AlertRule
+	alertStates     map[alertKey]*models.AlertState
+	nextAlertRule   uint
+	outbox          []models.OutboxEntry
+	nextOutboxID    uint64
+	deadLetters     []models.DeadLetter
+	nextDeadLetter  uint64
+	nextCalibration uint
+	nextID          uint
+	duplicatePolicy DuplicatePolicy
+	rules           ValidationRules
+}
+
+// NewInMemoryDatastore creates a thread safe Datastore that keeps all measurements in memory.
+// It mimics the behaviour of the Postgres implementation and is intended for tests and local
+// development without any external dependencies.
+func NewInMemoryDatastore(logger zerolog.Logger, policy DuplicatePolicy, rules ValidationRules) Datastore {
+	logger.Info().Msg("using an in-memory datastore, measurements will not be persisted")
+	return &inMemoryDB{
+		nextID:          1,
+		nextCalibration: 1,
+		health:          map[string]*models.DeviceHealth{},
+		alertStates:     map[alertKey]*models.AlertState{},
+		nextAlertRule:   1,
+		nextOutboxID:    1,
+		nextDeadLetter:  1,
+		duplicatePolicy: policy,
+		rules:           rules,
+	}
+}
+
+// Ping always succeeds for the in-memory datastore, unless ctx has been cancelled
+func (db *inMemoryDB) Ping(ctx context.Context) error {
+	return checkContext(ctx)
+}
+
+// Close is a no-op for the in-memory datastore
+func (db *inMemoryDB) Close() error {
+	return nil
+}
+
+// AddManualSnowdepthMeasurement takes a position and a depth and adds a record to the datastore,
+// together with an entry in the audit trail
+func (db *inMemoryDB) AddManualSnowdepthMeasurement(ctx context.Context, latitude, longitude, depth float64) (*models.Snowdepth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	t := time.Now().UTC()
+
+	measurement, err := newSnowdepth(NewMeasurement{nil, latitude, longitude, depth, t.Format(time.RFC3339Nano)})
+	if err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if rejection := db.validate(measurement); rejection != nil {
+		return nil, rejection
+	}
+
+	measurement, previous, _, err := db.store(measurement)
+	if err != nil {
+		return nil, err
+	}
+
+	db.appendAudit(newAuditEntry(ctx, AuditAdd, "", previous, measurement))
+
+	return measurement, nil
+}
+
+// AddSnowdepthMeasurement takes a device, position and a depth and adds a record to the datastore,
+// applying the duplicate policy if a measurement already exists for the device and timestamp
+func (db *inMemoryDB) AddSnowdepthMeasurement(ctx context.Context, device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, IngestOutcome, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, IngestInserted, err
+	}
+
+	measurement, err := newSnowdepth(NewMeasurement{device, latitude, longitude, depth, when})
+	if err != nil {
+		return nil, IngestInserted, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	calibrate([]*models.Snowdepth{measurement}, db.calibrations)
+
+	rejection := db.validate(measurement)
+	db.touchDevices([]*models.Snowdepth{measurement})
+
+	if rejection != nil {
+		return nil, IngestQuarantined, rejection
+	}
+
+	measurement, _, outcome, err := db.store(measurement)
+	return measurement, outcome, err
+}
+
+// validate applies the validation rules to a new measurement and quarantines it if it is
+// rejected. The caller must hold the lock.
+func (db *inMemoryDB) validate(measurement *models.Snowdepth) *RejectedError {
+	rejection := db.rules.validateMeasurements([]*models.Snowdepth{measurement}, func(m *models.Snowdepth) *models.Snowdepth {
+		var previous *models.Snowdepth
+		for idx := range db.depths {
+			d := &db.depths[idx]
+			if d.Device == m.Device && d.DeletedAt == nil && d.Timestamp.Before(m.Timestamp) {
+				if previous == nil || d.Timestamp.After(previous.Timestamp) {
+					previous = d
+				}
+			}
+		}
+		return previous
+	})[0]
+
+	if rejection != nil {
+		q := newQuarantinedSnowdepth(measurement, rejection)
+		q.ID = uint(len(db.quarantine) + 1)
+		db.quarantine = append(db.quarantine, *q)
+	}
+
+	return rejection
+}
+
+// GetQuarantinedSnowdepths returns the rejected measurements that match the query, most recent first
+func (db *inMemoryDB) GetQuarantinedSnowdepths(ctx context.Context, query QuarantineQuery) ([]models.QuarantinedSnowdepth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	quarantined := []models.QuarantinedSnowdepth{}
+
+	for idx := len(db.quarantine) - 1; idx >= 0; idx-- {
+		if query.Limit > 0 && uint64(len(quarantined)) >= query.Limit {
+			break
+		}
+		if query.matches(db.quarantine[idx]) {
+			quarantined = append(quarantined, db.quarantine[idx])
+		}
+	}
+
+	return quarantined, nil
+}
+
+// store inserts a measurement, or applies the duplicate policy if a measurement already exists
+// for the device and timestamp. It returns a copy of the stored measurement, as well as the
+// values of the existing measurement if there was one. The caller must hold the lock.
+func (db *inMemoryDB) store(measurement *models.Snowdepth) (*models.Snowdepth, *models.Snowdepth, IngestOutcome, error) {
+	// Enforce the same uniqueness constraint as idx_device_timestamp
+	for idx := range db.depths {
+		existing := &db.depths[idx]
+		if existing.Device != measurement.Device || !existing.Timestamp.Equal(measurement.Timestamp) {
+			continue
+		}
+
+		previous := *existing
+
+		outcome, overwrite, err := resolveDuplicate(db.duplicatePolicy, existing, measurement)
+		if err != nil {
+			return nil, nil, outcome, err
+		}
+
+		if overwrite {
+			existing.Latitude = measurement.Latitude
+			existing.Longitude = measurement.Longitude
+			existing.Depth = measurement.Depth
+			existing.RawDepth = measurement.RawDepth
+			existing.CalibrationID = measurement.CalibrationID
+			existing.UpdatedAt = time.Now().UTC()
+			db.enqueueStored(existing)
+			db.raiseAlerts(existing)
+		}
+
+		result := *existing
+		return &result, &previous, outcome, nil
+	}
+
+	now := time.Now().UTC()
+	measurement.ID = db.nextID
+	measurement.CreatedAt = now
+	measurement.UpdatedAt = now
+	db.nextID++
+
+	db.depths = append(db.depths, *measurement)
+	db.enqueueStored(measurement)
+	db.raiseAlerts(measurement)
+
+	return measurement, nil, IngestInserted, nil
+}
+
+// AddSnowdepthMeasurements adds a batch of measurements, reporting the result for each of them
+func (db *inMemoryDB) AddSnowdepthMeasurements(ctx context.Context, batch []NewMeasurement) ([]IngestResult, error) {
+	if len(batch) > MaxBatchSize {
+		return nil, newError(ErrValidation, "a batch may contain at most %d measurements, got %d", MaxBatchSize, len(batch))
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]IngestResult, len(batch))
+	for idx, m := range batch {
+		measurement, outcome, err := db.AddSnowdepthMeasurement(ctx, m.Device, m.Latitude, m.Longitude, m.Depth, m.When)
+		results[idx] = IngestResult{Measurement: measurement, Outcome: outcome, Err: err}
+	}
+
+	return results, nil
+}
+
+// CorrectSnowdepthMeasurement changes the depth and/or position of a stored measurement
+func (db *inMemoryDB) CorrectSnowdepthMeasurement(ctx context.Context, id uint, correction SnowdepthCorrection) (*models.Snowdepth, error) {
+	if err := correction.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	measurement, err := db.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *measurement
+
+	corrected := *measurement
+	correction.apply(&corrected)
+
+	if err = db.rules.checkCorrection(&corrected); err != nil {
+		return nil, err
+	}
+
+	*measurement = corrected
+	measurement.UpdatedAt = time.Now().UTC()
+	db.enqueueStored(measurement)
+	db.raiseAlerts(measurement)
+
+	result := *measurement
+	db.appendAudit(newAuditEntry(ctx, AuditCorrect, correction.ChangedBy, &before, &result))
+
+	return &result, nil
+}
+
+// RetractSnowdepthMeasurement soft deletes a measurement
+func (db *inMemoryDB) RetractSnowdepthMeasurement(ctx context.Context, id uint, change MeasurementChange) (*models.Snowdepth, error) {
+	if err := change.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	measurement, err := db.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *measurement
+
+	now := time.Now().UTC()
+	measurement.ChangedBy = change.ChangedBy
+	measurement.ChangeReason = change.Reason
+	measurement.UpdatedAt = now
+	measurement.DeletedAt = &now
+
+	result := *measurement
+	db.appendAudit(newAuditEntry(ctx, AuditRetract, change.ChangedBy, &before, &result))
+
+	return &result, nil
+}
+
+// appendAudit adds an entry to the audit trail. The caller must hold the lock.
+func (db *inMemoryDB) appendAudit(entry models.SnowdepthAuditEntry) {
+	entry.ID = uint(len(db.audit) + 1)
+	db.audit = append(db.audit, entry)
+}
+
+// GetAuditTrail returns the entries in the audit trail that match the query, most recent first
+func (db *inMemoryDB) GetAuditTrail(ctx context.Context, query AuditQuery) ([]models.SnowdepthAuditEntry, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	entries := []models.SnowdepthAuditEntry{}
+
+	for idx := len(db.audit) - 1; idx >= 0; idx-- {
+		if query.Limit > 0 && uint64(len(entries)) >= query.Limit {
+			break
+		}
+		if query.matches(db.audit[idx]) {
+			entries = append(entries, db.audit[idx])
+		}
+	}
+
+	return entries, nil
+}
+
+// find returns the measurement with the given id, unless it has been retracted. The caller
+// must hold the lock.
+func (db *inMemoryDB) find(id uint) (*models.Snowdepth, error) {
+	for idx := range db.depths {
+		if db.depths[idx].ID == id && db.depths[idx].DeletedAt == nil {
+			return &db.depths[idx], nil
+		}
+	}
+
+	return nil, newError(ErrNotFound, "no measurement with id %d", id)
+}
+
+// GetLatestSnowdepths returns the most recent value for all sensors, as well as
+// all manually added values during the last 24 hours
+func (db *inMemoryDB) GetLatestSnowdepths(ctx context.Context) ([]models.Snowdepth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	queryStart := time.Now().UTC().AddDate(0, 0, -1)
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	latestFromDevices := map[string]models.Snowdepth{}
+	latestManual := []models.Snowdepth{}
+
+	for _, d := range db.depths {
+		if d.DeletedAt != nil || !d.Timestamp.After(queryStart) {
+			continue
+		}
+
+		if d.Device == "" {
+			latestManual = append(latestManual, d)
+		} else if latest, ok := latestFromDevices[d.Device]; !ok || d.Timestamp.After(latest.Timestamp) {
+			latestFromDevices[d.Device] = d
+		}
+	}
+
+	result := make([]models.Snowdepth, 0, len(latestFromDevices)+len(latestManual))
+	for _, d := range latestFromDevices {
+		result = append(result, d)
+	}
+
+	// DISTINCT ON (device) returns the rows ordered by device
+	sort.Slice(result, func(i, j int) bool { return result[i].Device < result[j].Device })
+
+	return append(result, latestManual...), nil
+}
+
+// GetLatestSnowdepthsForDevice returns all values from a device during the last 24 hours
+func (db *inMemoryDB) GetLatestSnowdepthsForDevice(ctx context.Context, device string) ([]models.Snowdepth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	queryStart := time.Now().UTC().AddDate(0, 0, -1)
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	depths := []models.Snowdepth{}
+	for _, d := range db.depths {
+		if d.DeletedAt == nil && d.Device == device && d.Timestamp.After(queryStart) {
+			depths = append(depths, d)
+		}
+	}
+
+	return depths, nil
+}
+
+// GetSnowdepthHistory returns the measurements that match the query, where From is
+// inclusive and To is exclusive, including those that have been downsampled
+func (db *inMemoryDB) GetSnowdepthHistory(ctx context.Context, query SnowdepthQuery) ([]models.Snowdepth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if query.Area != nil {
+		if err := query.Area.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	db.mu.RLock()
+	depths := []models.Snowdepth{}
+	for _, d := range db.depths {
+		if d.DeletedAt == nil && matchesQuery(query, d.Device, d.Latitude, d.Longitude, d.Timestamp) {
+			depths = append(depths, d)
+		}
+	}
+
+	aggregated := []models.Snowdepth{}
+	for _, a := range db.aggregates {
+		if matchesQuery(query, a.Device, a.Latitude, a.Longitude, a.Start) {
+			aggregated = append(aggregated, aggregateToSnowdepth(a))
+		}
+	}
+	db.mu.RUnlock()
+
+	return mergeHistory(depths, aggregated, query.Order, query.Limit), nil
+}
+
+func matchesQuery(query SnowdepthQuery, device string, latitude, longitude float64, timestamp time.Time) bool {
+	if query.Device != nil && device != *query.Device {
+		return false
+	}
+	if !query.From.IsZero() && timestamp.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && !timestamp.Before(query.To) {
+		return false
+	}
+	if query.Area != nil && !query.Area.Contains(latitude, longitude) {
+		return false
+	}
+	return true
+}
+
+// GetSnowdepthStatistics returns the min, max, mean, count and last value per device and
+// interval for all measurements within the time span from (inclusive) to (exclusive),
+// including those that have been downsampled
+func (db *inMemoryDB) GetSnowdepthStatistics(ctx context.Context, device *string, from, to time.Time, interval AggregationInterval) ([]models.SnowdepthStatistics, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if interval != AggregateHourly && interval != AggregateDaily {
+		return nil, newError(ErrValidation, "unsupported aggregation interval %s", interval)
+	}
+
+	query := SnowdepthQuery{Device: device, From: from, To: to}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	raw := newStatisticsBuckets(interval)
+	for _, d := range db.depths {
+		if d.DeletedAt == nil && matchesQuery(query, d.Device, d.Latitude, d.Longitude, d.Timestamp) {
+			value := float64(d.Depth)
+			raw.add(d.Device, d.Timestamp, value, value, value, 1, value, d.Timestamp)
+		}
+	}
+
+	aggregated := newStatisticsBuckets(interval)
+	for _, a := range db.aggregates {
+		if matchesQuery(query, a.Device, a.Latitude, a.Longitude, a.Start) {
+			aggregated.add(a.Device, a.Start, a.Min, a.Max, a.Sum, a.Count, a.Last, a.LastTimestamp)
+		}
+	}
+
+	return mergeStatistics(raw.result(), aggregated.result()), nil
+}
+
+type statisticsBucketKey struct {
+	device string
+	start  time.Time
+}
+
+// statisticsBuckets computes statistics per device and interval from values that may
+// already have been aggregated
+type statisticsBuckets struct {
+	interval       AggregationInterval
+	buckets        map[statisticsBucketKey]*models.SnowdepthStatistics
+	lastTimestamps map[statisticsBucketKey]time.Time
+}
+
+func newStatisticsBuckets(interval AggregationInterval) *statisticsBuckets {
+	return &statisticsBuckets{
+		interval:       interval,
+		buckets:        map[statisticsBucketKey]*models.SnowdepthStatistics{},
+		lastTimestamps: map[statisticsBucketKey]time.Time{},
+	}
+}
+
+func (b *statisticsBuckets) add(device string, timestamp time.Time, min, max, sum float64, count int64, last float64, lastTimestamp time.Time) {
+	key := statisticsBucketKey{device: device, start: truncateToInterval(timestamp.UTC(), b.interval)}
+
+	stats, ok := b.buckets[key]
+	if !ok {
+		stats = &models.SnowdepthStatistics{Device: device, Start: key.start, Min: min, Max: max}
+		b.buckets[key] = stats
+	}
+
+	if min < stats.Min {
+		stats.Min = min
+	}
+	if max > stats.Max {
+		stats.Max = max
+	}
+
+	// Mean holds the running sum until all values have been added
+	stats.Mean += sum
+	stats.Count += count
+
+	if !lastTimestamp.Before(b.lastTimestamps[key]) {
+		stats.Last = last
+		b.lastTimestamps[key] = lastTimestamp
+	}
+}
+
+func (b *statisticsBuckets) result() []models.SnowdepthStatistics {
+	result := make([]models.SnowdepthStatistics, 0, len(b.buckets))
+	for _, stats := range b.buckets {
+		stats.Mean = stats.Mean / float64(stats.Count)
+		result = append(result, *stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Device != result[j].Device {
+			return result[i].Device < result[j].Device
+		}
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return result
+}
+
+// ApplyRetention downsamples and purges measurements according to the policy
+func (db *inMemoryDB) ApplyRetention(ctx context.Context, policy RetentionPolicy) (RetentionResult, error) {
+	if err := checkContext(ctx); err != nil {
+		return RetentionResult{}, err
+	}
+
+	result := RetentionResult{}
+	now := time.Now()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if policy.RawRetention > 0 {
+		before := policy.downsampleBefore(now)
+		kept := db.depths[:0]
+
+		for _, d := range db.depths {
+			if !d.Timestamp.Before(before) {
+				kept = append(kept, d)
+				continue
+			}
+
+			if d.DeletedAt == nil {
+				db.addToAggregate(d)
+			}
+			result.Downsampled++
+		}
+
+		db.depths = kept
+	}
+
+	if policy.PurgeAfterSeasons > 0 {
+		before := policy.purgeBefore(now)
+
+		keptDepths := db.depths[:0]
+		for _, d := range db.depths {
+			if d.Timestamp.Before(before) {
+				result.Purged++
+				continue
+			}
+			keptDepths = append(keptDepths, d)
+		}
+		db.depths = keptDepths
+
+		keptAggregates := db.aggregates[:0]
+		for _, a := range db.aggregates {
+			if a.Start.Before(before) {
+				result.Purged++
+				continue
+			}
+			keptAggregates = append(keptAggregates, a)
+		}
+		db.aggregates = keptAggregates
+	}
+
+	return result, nil
+}
+
+// addToAggregate merges a measurement into the aggregate for its device, position and hour
+func (db *inMemoryDB) addToAggregate(d models.Snowdepth) {
+	start := d.Timestamp.UTC().Truncate(time.Hour)
+	value := float64(d.Depth)
+
+	for idx := range db.aggregates {
+		a := &db.aggregates[idx]
+		if a.Device != d.Device || !a.Start.Equal(start) || a.Latitude != d.Latitude || a.Longitude != d.Longitude {
+			continue
+		}
+
+		a.Min = math.Min(a.Min, value)
+		a.Max = math.Max(a.Max, value)
+		a.Sum += value
+		a.Count++
+		if d.Timestamp.After(a.LastTimestamp) {
+			a.Last = value
+			a.LastTimestamp = d.Timestamp
+		}
+		return
+	}
+
+	db.aggregates = append(db.aggregates, models.SnowdepthAggregate{
+		Device:        d.Device,
+		Start:         start,
+		Latitude:      d.Latitude,
+		Longitude:     d.Longitude,
+		Min:           value,
+		Max:           value,
+		Sum:           value,
+		Count:         1,
+		Last:          value,
+		LastTimestamp: d.Timestamp,
+	})
+}
+
+func truncateToInterval(t time.Time, interval AggregationInterval) time.Time {
+	if interval == AggregateDaily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// GetDeviceCalibrations returns the calibrations of a device ordered by the time they start
+func (db *inMemoryDB) GetDeviceCalibrations(ctx context.Context, device string) ([]models.DeviceCalibration, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	calibrations := []models.DeviceCalibration{}
+	for _, c := range db.calibrations {
+		if c.Device == device {
+			calibrations = append(calibrations, c)
+		}
+	}
+
+	sort.Slice(calibrations, func(i, j int) bool {
+		return calibrations[i].ValidFrom.Before(calibrations[j].ValidFrom)
+	})
+
+	return calibrations, nil
+}
+
+// AddDeviceCalibration adds a calibration and optionally recomputes the affected depths
+func (db *inMemoryDB) AddDeviceCalibration(ctx context.Context, input Calibration, recompute bool) (CalibrationChange, error) {
+	if err := input.validate(); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := time.Now().UTC()
+	calibration := models.DeviceCalibration{ID: db.nextCalibration, CreatedAt: now, UpdatedAt: now}
+	input.apply(&calibration)
+
+	if err := db.checkOverlap(&calibration); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	db.nextCalibration++
+	db.calibrations = append(db.calibrations, calibration)
+
+	result := CalibrationChange{Calibration: &calibration}
+	if recompute {
+		result.Recomputed = db.recomputeDepths(calibration.Device, spanOf(&calibration))
+	}
+
+	return result, nil
+}
+
+// UpdateDeviceCalibration changes a calibration and optionally recomputes the affected depths
+func (db *inMemoryDB) UpdateDeviceCalibration(ctx context.Context, id uint, input Calibration, recompute bool) (CalibrationChange, error) {
+	if err := input.validate(); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stored, err := db.findCalibration(id)
+	if err != nil {
+		return CalibrationChange{}, err
+	}
+
+	if stored.Device != input.Device {
+		return CalibrationChange{}, newError(ErrValidation, "calibration %d belongs to device %q and can not be moved to another device", id, stored.Device)
+	}
+
+	calibration := *stored
+	input.apply(&calibration)
+	calibration.UpdatedAt = time.Now().UTC()
+
+	if err := db.checkOverlap(&calibration); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	affected := spanOf(stored).union(spanOf(&calibration))
+	*stored = calibration
+
+	result := CalibrationChange{Calibration: &calibration}
+	if recompute {
+		result.Recomputed = db.recomputeDepths(calibration.Device, affected)
+	}
+
+	return result, nil
+}
+
+// DeleteDeviceCalibration removes a calibration and optionally recomputes the affected depths
+func (db *inMemoryDB) DeleteDeviceCalibration(ctx context.Context, id uint, recompute bool) (CalibrationChange, error) {
+	if err := checkContext(ctx); err != nil {
+		return CalibrationChange{}, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stored, err := db.findCalibration(id)
+	if err != nil {
+		return CalibrationChange{}, err
+	}
+
+	calibration := *stored
+
+	remaining := db.calibrations[:0]
+	for _, c := range db.calibrations {
+		if c.ID != id {
+			remaining = append(remaining, c)
+		}
+	}
+	db.calibrations = remaining
+
+	for idx := range db.depths {
+		if d := &db.depths[idx]; d.CalibrationID != nil && *d.CalibrationID == id {
+			d.CalibrationID = nil
+		}
+	}
+
+	result := CalibrationChange{Calibration: &calibration}
+	if recompute {
+		result.Recomputed = db.recomputeDepths(calibration.Device, spanOf(&calibration))
+	}
+
+	return result, nil
+}
+
+// findCalibration returns the calibration with the given id. The caller must hold the lock.
+func (db *inMemoryDB) findCalibration(id uint) (*models.DeviceCalibration, error) {
+	for idx := range db.calibrations {
+		if db.calibrations[idx].ID == id {
+			return &db.calibrations[idx], nil
+		}
+	}
+	return nil, newError(ErrNotFound, "no calibration with id %d", id)
+}
+
+// checkOverlap fails if the calibration overlaps another calibration of the same device.
+// The caller must hold the lock.
+func (db *inMemoryDB) checkOverlap(calibration *models.DeviceCalibration) error {
+	for idx := range db.calibrations {
+		c := &db.calibrations[idx]
+		if c.Device == calibration.Device && c.ID != calibration.ID && spanOf(c).overlaps(spanOf(calibration)) {
+			return newError(ErrValidation, "the calibration overlaps calibration %d of device %q", c.ID, calibration.Device)
+		}
+	}
+	return nil
+}
+
+// recomputeDepths computes the depths of the measurements from a device during a span of
+// time from their raw values, except for those that have been corrected manually. The caller
+// must hold the lock.
+func (db *inMemoryDB) recomputeDepths(device string, span timeSpan) int64 {
+	affected := []*models.Snowdepth{}
+
+	for idx := range db.depths {
+		d := &db.depths[idx]
+		if d.Device == device && d.ChangedBy == "" && !d.Timestamp.Before(span.from) && (span.to == nil || d.Timestamp.Before(*span.to)) {
+			affected = append(affected, d)
+		}
+	}
+
+	calibrate(affected, db.calibrations)
+
+	return int64(len(affected))
+}
+
+// touchDevices records that measurements have been received from their devices. The caller
+// must hold the lock.
+func (db *inMemoryDB) touchDevices(measurements []*models.Snowdepth) {
+	now := time.Now().UTC()
+	devices, seen := lastSeen(measurements, now)
+
+	for _, device := range devices {
+		health, ok := db.health[device]
+		if !ok {
+			db.health[device] = &models.DeviceHealth{
+				Device: device, LastSeen: seen[device], Status: DeviceOnline, StatusChangedAt: now,
+			}
+		} else if seen[device].After(health.LastSeen) {
+			health.LastSeen = seen[device]
+		}
+	}
+}
+
+// GetDeviceHealth returns the health of a device, or fails with ErrNotFound if the device
+// has never reported a measurement
+func (db *inMemoryDB) GetDeviceHealth(ctx context.Context, device string) (*models.DeviceHealth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	health, ok := db.health[device]
+	if !ok {
+		return nil, newError(ErrNotFound, "no device with id %q", device)
+	}
+
+	result := *health
+	return &result, nil
+}
+
+// GetDevicesHealth returns the health of all devices ordered by device, optionally limited to
+// the devices that have a certain status
+func (db *inMemoryDB) GetDevicesHealth(ctx context.Context, status *string) ([]models.DeviceHealth, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	devices := []models.DeviceHealth{}
+	for _, health := range db.health {
+		if status == nil || health.Status == *status {
+			devices = append(devices, *health)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Device < devices[j].Device
+	})
+
+	return devices, nil
+}
+
+// SetDeviceExpectedInterval changes how often a device is expected to report, or makes it use
+// the default interval again if interval is nil
+func (db *inMemoryDB) SetDeviceExpectedInterval(ctx context.Context, device string, interval *time.Duration) (*models.DeviceHealth, error) {
+	if interval != nil && *interval < time.Second {
+		return nil, newError(ErrValidation, "the expected interval of a device must be at least a second")
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	health, ok := db.health[device]
+	if !ok {
+		return nil, newError(ErrNotFound, "no device with id %q", device)
+	}
+
+	health.ExpectedInterval = nil
+	if interval != nil {
+		seconds := int64(*interval / time.Second)
+		health.ExpectedInterval = &seconds
+	}
+
+	result := *health
+	return &result, nil
+}
+
+// UpdateDeviceStatuses applies the health policy to all devices and returns the devices that
+// changed status
+func (db *inMemoryDB) UpdateDeviceStatuses(ctx context.Context, policy HealthPolicy) (HealthResult, error) {
+	if err := checkContext(ctx); err != nil {
+		return HealthResult{}, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	devices := make([]models.DeviceHealth, 0, len(db.health))
+	for _, health := range db.health {
+		devices = append(devices, *health)
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Device < devices[j].Device
+	})
+
+	result := HealthResult{Changes: policy.updateStatuses(devices, time.Now().UTC())}
+
+	for idx := range devices {
+		*db.health[devices[idx].Device] = devices[idx]
+	}
+
+	return result, nil
+}
+
+// GetAlertRules returns all alert rules ordered by id
+func (db *inMemoryDB) GetAlertRules(ctx context.Context) ([]models.AlertRule, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	rules := make([]models.AlertRule, len(db.alertRules))
+	copy(rules, db.alertRules)
+
+	return rules, nil
+}
+
+// AddAlertRule adds an alert rule
+func (db *inMemoryDB) AddAlertRule(ctx context.Context, input AlertRuleInput) (*models.AlertRule, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := time.Now().UTC()
+	rule := models.AlertRule{ID: db.nextAlertRule, CreatedAt: now, UpdatedAt: now}
+	input.apply(&rule)
+
+	db.nextAlertRule++
+	db.alertRules = append(db.alertRules, rule)
+
+	return &rule, nil
+}
+
+// UpdateAlertRule changes an alert rule and resets its state
+func (db *inMemoryDB) UpdateAlertRule(ctx context.Context, id uint, input AlertRuleInput) (*models.AlertRule, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	idx, err := db.findAlertRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &db.alertRules[idx]
+	input.apply(rule)
+	rule.UpdatedAt = time.Now().UTC()
+
+	db.resetAlertStates(id)
+
+	result := *rule
+	return &result, nil
+}
+
+// DeleteAlertRule removes an alert rule together with its state
+func (db *inMemoryDB) DeleteAlertRule(ctx context.Context, id uint) (*models.AlertRule, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	idx, err := db.findAlertRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := db.alertRules[idx]
+	db.alertRules = append(db.alertRules[:idx], db.alertRules[idx+1:]...)
+	db.resetAlertStates(id)
+
+	return &rule, nil
+}
+
+// findAlertRule returns the index of the alert rule with the given id. The caller must hold the lock.
+func (db *inMemoryDB) findAlertRule(id uint) (int, error) {
+	for idx := range db.alertRules {
+		if db.alertRules[idx].ID == id {
+			return idx, nil
+		}
+	}
+	return 0, newError(ErrNotFound, "no alert rule with id %d", id)
+}
+
+// resetAlertStates removes the states of a rule. The caller must hold the lock.
+func (db *inMemoryDB) resetAlertStates(id uint) {
+	for key := range db.alertStates {
+		if key.rule == id {
+			delete(db.alertStates, key)
+		}
+	}
+}
+
+// enqueueStored adds a SnowdepthStored event for a measurement to the outbox. The caller must
+// hold the lock.
+func (db *inMemoryDB) enqueueStored(measurement *models.Snowdepth) {
+	entries, err := newOutboxEntries(storedEvents(measurement)...)
+	if err == nil {
+		db.enqueue(entries)
+	}
+}
+
+// raiseAlerts applies the enabled alert rules to a stored measurement and adds the alerts
+// that it raises to the outbox. The caller must hold the lock.
+func (db *inMemoryDB) raiseAlerts(measurement *models.Snowdepth) {
+	alerts := evaluateAlerts(db.alertRules, db.alertStates, []*models.Snowdepth{measurement}, time.Now().UTC())
+
+	entries, err := alertEntries(alerts)
+	if err == nil {
+		db.enqueue(entries)
+	}
+}
+
+// enqueue adds entries to the outbox. The caller must hold the lock.
+func (db *inMemoryDB) enqueue(entries []models.OutboxEntry) {
+	for _, entry := range entries {
+		entry.ID = db.nextOutboxID
+		db.nextOutboxID++
+		db.outbox = append(db.outbox, entry)
+	}
+}
+
+// RelayOutbox publishes up to limit messages from the outbox in the order that they were
+// added, and removes the ones that were published. Relaying stops at the first message that
+// can not be published on the message bus, while a webhook that fails is retried the next time.
+// The messages are published without holding the lock, so that ingest is not held up by a
+// slow broker or webhook, and concurrent relays wait for each other.
+func (db *inMemoryDB) RelayOutbox(ctx context.Context, limit int, publish func(context.Context, OutboxMessage) error) (OutboxResult, error) {
+	if err := checkContext(ctx); err != nil {
+		return OutboxResult{}, err
+	}
+
+	db.relayMu.Lock()
+	defer db.relayMu.Unlock()
+
+	db.mu.RLock()
+	pending := make([]models.OutboxEntry, 0, limit)
+	for idx := 0; idx < limit && idx < len(db.outbox); idx++ {
+		pending = append(pending, db.outbox[idx])
+	}
+	db.mu.RUnlock()
+
+	result := OutboxResult{}
+	published := map[uint64]bool{}
+	failed := map[uint64]string{}
+
+	for _, entry := range pending {
+		if err := publish(ctx, newOutboxMessage(entry)); err != nil {
+			failed[entry.ID] = err.Error()
+
+			if entry.Webhook != "" {
+				continue
+			}
+
+			result.Failed = true
+			break
+		}
+
+		published[entry.ID] = true
+		result.Published++
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	remaining := make([]models.OutboxEntry, 0, len(db.outbox)-len(published))
+	for _, entry := range db.outbox {
+		if published[entry.ID] {
+			continue
+		}
+
+		if lastError, ok := failed[entry.ID]; ok {
+			entry.Attempts++
+			entry.LastError = lastError
+		}
+
+		remaining = append(remaining, entry)
+	}
+
+	db.outbox = remaining
+
+	return result, nil
+}
+
+// AddDeadLetter stores a received message that could not be stored
+func (db *inMemoryDB) AddDeadLetter(ctx context.Context, d NewDeadLetter) (*models.DeadLetter, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	letter := newDeadLetter(d)
+	letter.ID = db.nextDeadLetter
+	db.nextDeadLetter++
+
+	db.deadLetters = append(db.deadLetters, *letter)
+
+	return letter, nil
+}
+
+// GetDeadLetters returns the dead letters that match the query, most recent first
+func (db *inMemoryDB) GetDeadLetters(ctx context.Context, query DeadLetterQuery) ([]models.DeadLetter, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	letters := []models.DeadLetter{}
+
+	for idx := len(db.deadLetters) - 1; idx >= 0; idx-- {
+		if query.Limit > 0 && uint64(len(letters)) >= query.Limit {
+			break
+		}
+		if query.matches(db.deadLetters[idx]) {
+			letters = append(letters, db.deadLetters[idx])
+		}
+	}
+
+	return letters, nil
+}
+
+// GetDeadLetter returns a single dead letter
+func (db *inMemoryDB) GetDeadLetter(ctx context.Context, id uint64) (*models.DeadLetter, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	idx, err := db.findDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	letter := db.deadLetters[idx]
+	return &letter, nil
+}
+
+// RecordDeadLetterReplay counts an attempt to replay a dead letter, and marks it as replayed
+// unless the attempt failed with replayErr
+func (db *inMemoryDB) RecordDeadLetterReplay(ctx context.Context, id uint64, replayErr error) (*models.DeadLetter, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	idx, err := db.findDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	letter := &db.deadLetters[idx]
+	letter.Attempts++
+
+	if replayErr != nil {
+		letter.Error = replayErr.Error()
+	} else {
+		now := time.Now().UTC()
+		letter.ReplayedAt = &now
+	}
+
+	replayed := *letter
+	return &replayed, nil
+}
+
+// findDeadLetter returns the index of a dead letter. The caller must hold the lock.
+func (db *inMemoryDB) findDeadLetter(id uint64) (int, error) {
+	for idx := range db.deadLetters {
+		if db.deadLetters[idx].ID == id {
+			return idx, nil
+		}
+	}
+
+	return 0, newError(ErrNotFound, "no dead letter with id %d", id)
+}
@@ -0,0 +1,451 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+var testStart = time.Date(2022, time.January, 10, 10, 0, 0, 0, time.UTC)
+
+var testRules = ValidationRules{MinDepth: 0, MaxDepth: 1000, MaxFutureSkew: 5 * time.Minute}
+
+func newTestDatastore(t *testing.T, policy DuplicatePolicy, rules ValidationRules) Datastore {
+	t.Helper()
+	return NewInMemoryDatastore(zerolog.Nop(), policy, rules)
+}
+
+// addMeasurement adds a measurement from device at the given offset from testStart and fails
+// the test if it was not inserted
+func addMeasurement(t *testing.T, db Datastore, device string, offset time.Duration, depth float64) *models.Snowdepth {
+	t.Helper()
+
+	when := testStart.Add(offset).Format(time.RFC3339)
+	m, outcome, err := db.AddSnowdepthMeasurement(context.Background(), &device, 62.39, 17.30, depth, when)
+	if err != nil {
+		t.Fatalf("failed to add measurement at %s: %s", when, err)
+	}
+	if outcome != IngestInserted {
+		t.Fatalf("expected measurement at %s to be inserted, got %s", when, outcome)
+	}
+
+	return m
+}
+
+func depthsOf(measurements []models.Snowdepth) []float32 {
+	depths := make([]float32, 0, len(measurements))
+	for _, m := range measurements {
+		depths = append(depths, m.Depth)
+	}
+	return depths
+}
+
+func equalDepths(a, b []float32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInMemoryHistoryOrderAndLimit(t *testing.T) {
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	// Added out of order so that the order of the result can not come from insertion
+	addMeasurement(t, db, "a", 2*time.Hour, 30)
+	addMeasurement(t, db, "a", 0, 10)
+	addMeasurement(t, db, "b", 30*time.Minute, 15)
+	addMeasurement(t, db, "a", time.Hour, 20)
+
+	a := "a"
+
+	tests := []struct {
+		name     string
+		query    SnowdepthQuery
+		expected []float32
+	}{
+		{"all ascending", SnowdepthQuery{}, []float32{10, 15, 20, 30}},
+		{"all descending", SnowdepthQuery{Order: SortDescending}, []float32{30, 20, 15, 10}},
+		{"ascending with limit", SnowdepthQuery{Limit: 2}, []float32{10, 15}},
+		{"descending with limit", SnowdepthQuery{Order: SortDescending, Limit: 2}, []float32{30, 20}},
+		{"limit above count", SnowdepthQuery{Limit: 10}, []float32{10, 15, 20, 30}},
+		{"device", SnowdepthQuery{Device: &a}, []float32{10, 20, 30}},
+		{"from is inclusive", SnowdepthQuery{From: testStart.Add(time.Hour)}, []float32{20, 30}},
+		{"to is exclusive", SnowdepthQuery{To: testStart.Add(time.Hour)}, []float32{10, 15}},
+		{"device with time span and limit", SnowdepthQuery{Device: &a, From: testStart, To: testStart.Add(3 * time.Hour), Order: SortDescending, Limit: 1}, []float32{30}},
+		{"outside of area", SnowdepthQuery{Area: &Area{Box: &BoundingBox{South: 10, West: 10, North: 11, East: 11}}}, []float32{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			history, err := db.GetSnowdepthHistory(context.Background(), tc.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if depths := depthsOf(history); !equalDepths(depths, tc.expected) {
+				t.Errorf("expected depths %v, got %v", tc.expected, depths)
+			}
+		})
+	}
+}
+
+func TestInMemoryStatistics(t *testing.T) {
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	addMeasurement(t, db, "a", 0, 10)
+	addMeasurement(t, db, "a", 30*time.Minute, 20)
+	addMeasurement(t, db, "a", 90*time.Minute, 40)
+	addMeasurement(t, db, "b", 15*time.Minute, 5)
+
+	a := "a"
+
+	tests := []struct {
+		name     string
+		device   *string
+		from, to time.Time
+		interval AggregationInterval
+		expected []models.SnowdepthStatistics
+	}{
+		{
+			name: "hourly", device: &a, interval: AggregateHourly,
+			expected: []models.SnowdepthStatistics{
+				{Device: "a", Start: testStart, Min: 10, Max: 20, Mean: 15, Count: 2, Last: 20},
+				{Device: "a", Start: testStart.Add(time.Hour), Min: 40, Max: 40, Mean: 40, Count: 1, Last: 40},
+			},
+		},
+		{
+			name: "daily for all devices", interval: AggregateDaily,
+			expected: []models.SnowdepthStatistics{
+				{Device: "a", Start: testStart.Truncate(24 * time.Hour), Min: 10, Max: 40, Mean: 70.0 / 3, Count: 3, Last: 40},
+				{Device: "b", Start: testStart.Truncate(24 * time.Hour), Min: 5, Max: 5, Mean: 5, Count: 1, Last: 5},
+			},
+		},
+		{
+			name: "time span", device: &a, from: testStart.Add(20 * time.Minute), to: testStart.Add(time.Hour), interval: AggregateHourly,
+			expected: []models.SnowdepthStatistics{
+				{Device: "a", Start: testStart, Min: 20, Max: 20, Mean: 20, Count: 1, Last: 20},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stats, err := db.GetSnowdepthStatistics(context.Background(), tc.device, tc.from, tc.to, tc.interval)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(stats) != len(tc.expected) {
+				t.Fatalf("expected %d buckets, got %d: %+v", len(tc.expected), len(stats), stats)
+			}
+
+			for idx, expected := range tc.expected {
+				if !stats[idx].Start.Equal(expected.Start) {
+					t.Errorf("bucket %d: expected start %s, got %s", idx, expected.Start, stats[idx].Start)
+				}
+				stats[idx].Start = expected.Start
+
+				if stats[idx] != expected {
+					t.Errorf("bucket %d: expected %+v, got %+v", idx, expected, stats[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryStatisticsRejectsUnknownInterval(t *testing.T) {
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	_, err := db.GetSnowdepthStatistics(context.Background(), nil, time.Time{}, time.Time{}, "week")
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestInMemoryDuplicatePolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          DuplicatePolicy
+		depth           float64
+		retracted       bool
+		corrected       bool
+		expectedOutcome IngestOutcome
+		expectedErr     error
+		expectedDepth   float32
+	}{
+		{"ignore identical", DuplicateIgnore, 10, false, false, IngestDuplicate, nil, 10},
+		{"ignore different", DuplicateIgnore, 20, false, false, IngestConflictIgnored, nil, 10},
+		{"overwrite identical", DuplicateOverwrite, 10, false, false, IngestDuplicate, nil, 10},
+		{"overwrite different", DuplicateOverwrite, 20, false, false, IngestOverwritten, nil, 20},
+		{"reject identical", DuplicateRejectIfDifferent, 10, false, false, IngestDuplicate, nil, 10},
+		{"reject different", DuplicateRejectIfDifferent, 20, false, false, IngestConflictIgnored, ErrConflictingMeasurement, 10},
+		{"overwrite retracted", DuplicateOverwrite, 20, true, false, IngestConflictIgnored, nil, 10},
+		{"reject retracted", DuplicateRejectIfDifferent, 20, true, false, IngestConflictIgnored, nil, 10},
+		{"overwrite corrected", DuplicateOverwrite, 20, false, true, IngestConflictIgnored, nil, 15},
+		{"reject corrected", DuplicateRejectIfDifferent, 20, false, true, IngestConflictIgnored, nil, 15},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestDatastore(t, tc.policy, testRules)
+
+			stored := addMeasurement(t, db, "a", 0, 10)
+
+			if tc.corrected {
+				depth := 15.0
+				_, err := db.CorrectSnowdepthMeasurement(ctx, stored.ID, SnowdepthCorrection{
+					MeasurementChange: MeasurementChange{ChangedBy: "test", Reason: "measured by hand"},
+					Depth:             &depth,
+				})
+				if err != nil {
+					t.Fatalf("failed to correct measurement: %s", err)
+				}
+			}
+
+			if tc.retracted {
+				_, err := db.RetractSnowdepthMeasurement(ctx, stored.ID, MeasurementChange{ChangedBy: "test", Reason: "faulty sensor"})
+				if err != nil {
+					t.Fatalf("failed to retract measurement: %s", err)
+				}
+			}
+
+			device := "a"
+			_, outcome, err := db.AddSnowdepthMeasurement(ctx, &device, 62.39, 17.30, tc.depth, testStart.Format(time.RFC3339))
+
+			if outcome != tc.expectedOutcome {
+				t.Errorf("expected outcome %s, got %s", tc.expectedOutcome, outcome)
+			}
+
+			if tc.expectedErr == nil && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+
+			history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{Device: &device})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tc.retracted {
+				if len(history) != 0 {
+					t.Errorf("expected the measurement to stay retracted, got %v", depthsOf(history))
+				}
+				return
+			}
+
+			if depths := depthsOf(history); !equalDepths(depths, []float32{tc.expectedDepth}) {
+				t.Errorf("expected stored depth %v, got %v", tc.expectedDepth, depths)
+			}
+		})
+	}
+}
+
+func TestInMemoryValidationQuarantinesRejectedMeasurements(t *testing.T) {
+	rules := ValidationRules{
+		MinDepth:         0,
+		MaxDepth:         300,
+		MaxChangePerHour: 10,
+		Fence:            &Area{Box: &BoundingBox{South: 55, West: 10, North: 70, East: 25}},
+		MaxFutureSkew:    5 * time.Minute,
+	}
+
+	tests := []struct {
+		name         string
+		latitude     float64
+		longitude    float64
+		depth        float64
+		when         time.Time
+		expectedRule string
+	}{
+		{"accepted", 62.39, 17.30, 25, testStart.Add(time.Hour), ""},
+		{"accepted slow change over hours", 62.39, 17.30, 45, testStart.Add(3 * time.Hour), ""},
+		{"too deep", 62.39, 17.30, 301, testStart.Add(time.Hour), RuleDepthRange},
+		{"negative depth", 62.39, 17.30, -1, testStart.Add(time.Hour), RuleDepthRange},
+		{"null island", 0, 0, 20, testStart.Add(time.Hour), RulePosition},
+		{"invalid latitude", 91, 17.30, 20, testStart.Add(time.Hour), RulePosition},
+		{"outside of fence", 40.0, 17.30, 20, testStart.Add(time.Hour), RuleGeofence},
+		{"rapid change", 62.39, 17.30, 45, testStart.Add(time.Hour), RuleRateOfChange},
+		{"future timestamp", 62.39, 17.30, 20, time.Now().UTC().Add(time.Hour), RuleFuture},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestDatastore(t, DuplicateIgnore, rules)
+
+			addMeasurement(t, db, "a", 0, 20)
+
+			device := "a"
+			_, outcome, err := db.AddSnowdepthMeasurement(ctx, &device, tc.latitude, tc.longitude, tc.depth, tc.when.Format(time.RFC3339))
+
+			quarantined, qerr := db.GetQuarantinedSnowdepths(ctx, QuarantineQuery{})
+			if qerr != nil {
+				t.Fatalf("unexpected error: %s", qerr)
+			}
+
+			if tc.expectedRule == "" {
+				if err != nil || outcome != IngestInserted {
+					t.Fatalf("expected measurement to be inserted, got %s: %v", outcome, err)
+				}
+				if len(quarantined) != 0 {
+					t.Errorf("expected nothing in the quarantine, got %d measurements", len(quarantined))
+				}
+				return
+			}
+
+			if outcome != IngestQuarantined {
+				t.Errorf("expected outcome %s, got %s", IngestQuarantined, outcome)
+			}
+
+			rejection := &RejectedError{}
+			if !errors.As(err, &rejection) {
+				t.Fatalf("expected a rejection, got %v", err)
+			}
+			if rejection.Rule != tc.expectedRule {
+				t.Errorf("expected the %s rule to reject the measurement, got %s", tc.expectedRule, rejection.Rule)
+			}
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("expected the rejection to be a validation error")
+			}
+
+			if len(quarantined) != 1 || quarantined[0].Rule != tc.expectedRule {
+				t.Errorf("expected one measurement quarantined by the %s rule, got %+v", tc.expectedRule, quarantined)
+			}
+
+			history, _ := db.GetSnowdepthHistory(ctx, SnowdepthQuery{Device: &device})
+			if len(history) != 1 {
+				t.Errorf("expected the rejected measurement not to be stored, got %v", depthsOf(history))
+			}
+		})
+	}
+}
+
+func TestInMemoryCalibrationRecompute(t *testing.T) {
+	validFrom := testStart.Add(time.Hour)
+	validTo := testStart.Add(3 * time.Hour)
+	height := 100.0
+
+	tests := []struct {
+		name               string
+		change             func(ctx context.Context, db Datastore, id uint) (CalibrationChange, error)
+		expectedRecomputed int64
+		expectedDepths     []float32
+	}{
+		{
+			name: "update",
+			change: func(ctx context.Context, db Datastore, id uint) (CalibrationChange, error) {
+				return db.UpdateDeviceCalibration(ctx, id, Calibration{Device: "a", Offset: 0, Scale: 3, ValidFrom: validFrom, ValidTo: &validTo}, true)
+			},
+			expectedRecomputed: 2,
+			expectedDepths:     []float32{10, 30, 60, 30},
+		},
+		{
+			name: "update to distance sensor",
+			change: func(ctx context.Context, db Datastore, id uint) (CalibrationChange, error) {
+				return db.UpdateDeviceCalibration(ctx, id, Calibration{Device: "a", MountingHeight: &height, Scale: 1, ValidFrom: validFrom, ValidTo: &validTo}, true)
+			},
+			expectedRecomputed: 2,
+			expectedDepths:     []float32{10, 90, 80, 30},
+		},
+		{
+			name: "update without recompute",
+			change: func(ctx context.Context, db Datastore, id uint) (CalibrationChange, error) {
+				return db.UpdateDeviceCalibration(ctx, id, Calibration{Device: "a", Offset: 0, Scale: 3, ValidFrom: validFrom, ValidTo: &validTo}, false)
+			},
+			expectedRecomputed: 0,
+			expectedDepths:     []float32{10, 21, 41, 30},
+		},
+		{
+			name: "delete",
+			change: func(ctx context.Context, db Datastore, id uint) (CalibrationChange, error) {
+				return db.DeleteDeviceCalibration(ctx, id, true)
+			},
+			expectedRecomputed: 2,
+			expectedDepths:     []float32{10, 10, 20, 30},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+			addMeasurement(t, db, "a", 0, 10)
+			addMeasurement(t, db, "a", time.Hour, 10)
+			addMeasurement(t, db, "a", 2*time.Hour, 20)
+			addMeasurement(t, db, "a", 3*time.Hour, 30)
+
+			added, err := db.AddDeviceCalibration(ctx, Calibration{Device: "a", Offset: 1, Scale: 2, ValidFrom: validFrom, ValidTo: &validTo}, true)
+			if err != nil {
+				t.Fatalf("failed to add calibration: %s", err)
+			}
+			if added.Recomputed != 2 {
+				t.Errorf("expected 2 measurements to be recomputed when adding the calibration, got %d", added.Recomputed)
+			}
+
+			changed, err := tc.change(ctx, db, added.Calibration.ID)
+			if err != nil {
+				t.Fatalf("failed to change calibration: %s", err)
+			}
+			if changed.Recomputed != tc.expectedRecomputed {
+				t.Errorf("expected %d measurements to be recomputed, got %d", tc.expectedRecomputed, changed.Recomputed)
+			}
+
+			history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if depths := depthsOf(history); !equalDepths(depths, tc.expectedDepths) {
+				t.Errorf("expected depths %v, got %v", tc.expectedDepths, depths)
+			}
+		})
+	}
+}
+
+func TestInMemoryCalibrationIsAppliedOnIngestAndKeepsCorrections(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	corrected := addMeasurement(t, db, "a", time.Hour, 10)
+
+	depth := 15.0
+	_, err := db.CorrectSnowdepthMeasurement(ctx, corrected.ID, SnowdepthCorrection{
+		MeasurementChange: MeasurementChange{ChangedBy: "test", Reason: "measured by hand"},
+		Depth:             &depth,
+	})
+	if err != nil {
+		t.Fatalf("failed to correct measurement: %s", err)
+	}
+
+	_, err = db.AddDeviceCalibration(ctx, Calibration{Device: "a", Offset: 1, Scale: 2, ValidFrom: testStart}, true)
+	if err != nil {
+		t.Fatalf("failed to add calibration: %s", err)
+	}
+
+	m := addMeasurement(t, db, "a", 2*time.Hour, 10)
+	if m.Depth != 21 || m.RawDepth != 10 || m.CalibrationID == nil {
+		t.Errorf("expected the calibration to be applied to a new measurement, got depth %v from raw %v", m.Depth, m.RawDepth)
+	}
+
+	history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if depths := depthsOf(history); !equalDepths(depths, []float32{15, 21}) {
+		t.Errorf("expected the corrected depth to be kept, got %v", depths)
+	}
+}
@@ -0,0 +1,553 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/rs/zerolog"
+)
+
+type migration struct {
+	version     uint
+	description string
+	up          string
+	down        string
+	// before is the version of an earlier migration that this migration is applied ahead of
+	// when both are pending, so that a later migration can prepare the data that an earlier
+	// one converts in databases that have not been converted yet
+	before uint
+}
+
+// migrations contains the ordered list of schema changes. Applied migrations must never be
+// modified, add a new migration with a higher version number instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create snowdepths table",
+		// The table may already have been created by AutoMigrate in earlier versions of this service
+		up: `
+			CREATE TABLE IF NOT EXISTS snowdepths (
+				id serial PRIMARY KEY,
+				created_at timestamp with time zone,
+				updated_at timestamp with time zone,
+				deleted_at timestamp with time zone,
+				latitude numeric,
+				longitude numeric,
+				device text,
+				depth numeric,
+				timestamp text
+			);
+			CREATE INDEX IF NOT EXISTS idx_snowdepths_deleted_at ON snowdepths (deleted_at);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_device_timestamp ON snowdepths (device, timestamp);`,
+		down: `DROP TABLE snowdepths;`,
+	},
+	{
+		version:     2,
+		description: "convert snowdepths.timestamp to timestamptz",
+		up:          `ALTER TABLE snowdepths ALTER COLUMN timestamp TYPE timestamptz USING timestamp::timestamptz;`,
+		down: `ALTER TABLE snowdepths ALTER COLUMN timestamp TYPE text
+			USING to_char(timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"');`,
+	},
+	{
+		version:     3,
+		description: "add spatial index on snowdepth positions",
+		up:          `CREATE INDEX IF NOT EXISTS idx_snowdepths_position ON snowdepths USING gist (point(longitude::float8, latitude::float8));`,
+		down:        `DROP INDEX IF EXISTS idx_snowdepths_position;`,
+	},
+	{
+		version:     4,
+		description: "create snowdepth_aggregates table for downsampled measurements",
+		up: `
+			CREATE TABLE snowdepth_aggregates (
+				id serial PRIMARY KEY,
+				device text NOT NULL,
+				start timestamptz NOT NULL,
+				latitude numeric NOT NULL,
+				longitude numeric NOT NULL,
+				min numeric NOT NULL,
+				max numeric NOT NULL,
+				sum numeric NOT NULL,
+				count bigint NOT NULL,
+				last numeric NOT NULL,
+				last_timestamp timestamptz NOT NULL
+			);
+			CREATE UNIQUE INDEX idx_aggregates_device_start_position ON snowdepth_aggregates (device, start, latitude, longitude);
+			CREATE INDEX idx_aggregates_start ON snowdepth_aggregates (start);
+			CREATE INDEX idx_aggregates_position ON snowdepth_aggregates USING gist (point(longitude::float8, latitude::float8));`,
+		down: `DROP TABLE snowdepth_aggregates;`,
+	},
+	{
+		version:     5,
+		description: "create latest_snowdepths table with the most recent measurement per device",
+		up: `
+			CREATE TABLE latest_snowdepths (
+				device text PRIMARY KEY,
+				snowdepth_id integer NOT NULL REFERENCES snowdepths (id) ON DELETE CASCADE,
+				timestamp timestamptz NOT NULL
+			);
+			CREATE INDEX idx_latest_snowdepths_timestamp ON latest_snowdepths (timestamp);
+			CREATE INDEX idx_latest_snowdepths_snowdepth_id ON latest_snowdepths (snowdepth_id);
+			CREATE INDEX idx_snowdepths_manual_timestamp ON snowdepths (timestamp) WHERE device = '';
+			INSERT INTO latest_snowdepths (device, snowdepth_id, timestamp)
+				SELECT DISTINCT ON (device) device, id, timestamp FROM snowdepths
+				WHERE device <> '' AND deleted_at IS NULL
+				ORDER BY device, timestamp DESC;`,
+		down: `
+			DROP INDEX IF EXISTS idx_snowdepths_manual_timestamp;
+			DROP TABLE latest_snowdepths;`,
+	},
+	{
+		version:     6,
+		description: "record who changed a measurement and why",
+		up: `
+			ALTER TABLE snowdepths ADD COLUMN changed_by text NOT NULL DEFAULT '';
+			ALTER TABLE snowdepths ADD COLUMN change_reason text NOT NULL DEFAULT '';`,
+		down: `
+			ALTER TABLE snowdepths DROP COLUMN change_reason;
+			ALTER TABLE snowdepths DROP COLUMN changed_by;`,
+	},
+	{
+		version:     7,
+		description: "create append-only snowdepth_audit table",
+		// snowdepth_id is deliberately not a foreign key, since the audit trail must outlive
+		// measurements that are purged by the retention job
+		up: `
+			CREATE TABLE snowdepth_audit (
+				id bigserial PRIMARY KEY,
+				created_at timestamptz NOT NULL DEFAULT now(),
+				operation text NOT NULL,
+				snowdepth_id integer NOT NULL,
+				actor text NOT NULL,
+				identity text NOT NULL,
+				client_ip text NOT NULL,
+				user_agent text NOT NULL,
+				before jsonb,
+				after jsonb
+			);
+			CREATE INDEX idx_snowdepth_audit_created_at ON snowdepth_audit (created_at);
+			CREATE INDEX idx_snowdepth_audit_snowdepth_id ON snowdepth_audit (snowdepth_id);
+			CREATE INDEX idx_snowdepth_audit_actor ON snowdepth_audit (actor);
+			CREATE FUNCTION snowdepth_audit_append_only() RETURNS trigger AS $$
+			BEGIN
+				RAISE EXCEPTION 'snowdepth_audit is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+			CREATE TRIGGER snowdepth_audit_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON snowdepth_audit
+				FOR EACH STATEMENT EXECUTE PROCEDURE snowdepth_audit_append_only();`,
+		down: `
+			DROP TABLE snowdepth_audit;
+			DROP FUNCTION snowdepth_audit_append_only();`,
+	},
+	{
+		version:     8,
+		description: "create snowdepth_quarantine table for rejected measurements",
+		up: `
+			CREATE TABLE snowdepth_quarantine (
+				id serial PRIMARY KEY,
+				created_at timestamptz NOT NULL,
+				device text NOT NULL,
+				latitude numeric NOT NULL,
+				longitude numeric NOT NULL,
+				depth numeric NOT NULL,
+				timestamp timestamptz NOT NULL,
+				rule text NOT NULL,
+				reason text NOT NULL
+			);
+			CREATE INDEX idx_snowdepth_quarantine_created_at ON snowdepth_quarantine (created_at);
+			CREATE INDEX idx_snowdepth_quarantine_device ON snowdepth_quarantine (device, timestamp);`,
+		down: `DROP TABLE snowdepth_quarantine;`,
+	},
+	{
+		version:     9,
+		description: "create device_calibrations table and keep raw depths",
+		up: `
+			CREATE TABLE device_calibrations (
+				id serial PRIMARY KEY,
+				created_at timestamptz NOT NULL,
+				updated_at timestamptz NOT NULL,
+				device text NOT NULL,
+				mounting_height numeric,
+				depth_offset numeric NOT NULL DEFAULT 0,
+				depth_scale numeric NOT NULL DEFAULT 1,
+				valid_from timestamptz NOT NULL,
+				valid_to timestamptz,
+				CHECK (valid_to IS NULL OR valid_to > valid_from)
+			);
+			CREATE INDEX idx_device_calibrations_device_valid_from ON device_calibrations (device, valid_from);
+			ALTER TABLE snowdepths ADD COLUMN raw_depth numeric;
+			UPDATE snowdepths SET raw_depth = depth;
+			ALTER TABLE snowdepths ALTER COLUMN raw_depth SET NOT NULL;
+			ALTER TABLE snowdepths ADD COLUMN calibration_id integer REFERENCES device_calibrations (id) ON DELETE SET NULL;
+			CREATE INDEX idx_snowdepths_calibration_id ON snowdepths (calibration_id);
+			ALTER TABLE snowdepth_quarantine ADD COLUMN raw_depth numeric;
+			UPDATE snowdepth_quarantine SET raw_depth = depth;
+			ALTER TABLE snowdepth_quarantine ALTER COLUMN raw_depth SET NOT NULL;`,
+		down: `
+			ALTER TABLE snowdepth_quarantine DROP COLUMN raw_depth;
+			ALTER TABLE snowdepths DROP COLUMN calibration_id;
+			ALTER TABLE snowdepths DROP COLUMN raw_depth;
+			DROP TABLE device_calibrations;`,
+	},
+	{
+		version:     10,
+		description: "create device_health table",
+		up: `
+			CREATE TABLE device_health (
+				device text PRIMARY KEY,
+				last_seen timestamptz NOT NULL,
+				expected_interval bigint CHECK (expected_interval > 0),
+				status text NOT NULL DEFAULT '',
+				status_changed_at timestamptz NOT NULL
+			);
+			INSERT INTO device_health (device, last_seen, status_changed_at)
+			SELECT device, timestamp, now() FROM latest_snowdepths;`,
+		down: `DROP TABLE device_health;`,
+	},
+	{
+		version:     11,
+		description: "create alert_rules and alert_states tables",
+		up: `
+			CREATE TABLE alert_rules (
+				id serial PRIMARY KEY,
+				created_at timestamptz NOT NULL,
+				updated_at timestamptz NOT NULL,
+				name text NOT NULL,
+				device text,
+				site_latitude double precision,
+				site_longitude double precision,
+				site_radius double precision,
+				direction text NOT NULL CHECK (direction IN ('above', 'below')),
+				threshold double precision NOT NULL,
+				hysteresis double precision NOT NULL DEFAULT 0 CHECK (hysteresis >= 0),
+				cooldown bigint NOT NULL DEFAULT 0 CHECK (cooldown >= 0),
+				enabled boolean NOT NULL DEFAULT true,
+				webhook_url text NOT NULL DEFAULT '',
+				CHECK ((site_latitude IS NULL) = (site_longitude IS NULL) AND (site_latitude IS NULL) = (site_radius IS NULL))
+			);
+			CREATE TABLE alert_states (
+				rule_id integer NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+				device text NOT NULL,
+				active boolean NOT NULL DEFAULT false,
+				last_timestamp timestamptz,
+				last_alert_at timestamptz,
+				PRIMARY KEY (rule_id, device)
+			);`,
+		down: `
+			DROP TABLE alert_states;
+			DROP TABLE alert_rules;`,
+	},
+	{
+		version:     12,
+		description: "create outbox table for events that are published after commit",
+		up: `
+			CREATE TABLE outbox (
+				id bigserial PRIMARY KEY,
+				created_at timestamptz NOT NULL,
+				topic text NOT NULL,
+				content_type text NOT NULL,
+				body jsonb NOT NULL,
+				attempts integer NOT NULL DEFAULT 0,
+				last_error text NOT NULL DEFAULT ''
+			);`,
+		down: `DROP TABLE outbox;`,
+	},
+	{
+		version:     13,
+		description: "create dead_letters table for received messages that could not be stored",
+		up: `
+			CREATE TABLE dead_letters (
+				id bigserial PRIMARY KEY,
+				created_at timestamptz NOT NULL,
+				routing_key text NOT NULL,
+				content_type text NOT NULL DEFAULT '',
+				body text NOT NULL,
+				reason text NOT NULL CHECK (reason IN ('malformed', 'rejected', 'retries-exhausted')),
+				error text NOT NULL DEFAULT '',
+				attempts integer NOT NULL DEFAULT 0,
+				replayed_at timestamptz
+			);
+			CREATE INDEX idx_dead_letters_created_at ON dead_letters (created_at);`,
+		down: `DROP TABLE dead_letters;`,
+	},
+	{
+		version:     14,
+		description: "add webhook column to outbox for alerts that are posted to webhooks",
+		up: `
+			ALTER TABLE outbox ADD COLUMN webhook text NOT NULL DEFAULT '';`,
+		down: `ALTER TABLE outbox DROP COLUMN webhook;`,
+	},
+	{
+		version:     15,
+		description: "move measurements that can not be converted to timestamptz to snowdepths_unconverted",
+		// Timestamps that can not be parsed, and measurements from the same device that are
+		// equal once their timestamps have been converted, make migration 2 fail. Databases
+		// that have already been converted have no such rows, and nothing is moved.
+		before: 2,
+		up: `
+			CREATE TABLE snowdepths_unconverted (
+				id integer PRIMARY KEY,
+				created_at timestamptz,
+				updated_at timestamptz,
+				deleted_at timestamptz,
+				latitude numeric,
+				longitude numeric,
+				device text,
+				depth numeric,
+				timestamp text,
+				reason text NOT NULL,
+				moved_at timestamptz NOT NULL DEFAULT now()
+			);
+			CREATE FUNCTION snowdepths_parse_timestamp(value text) RETURNS timestamptz AS $$
+			BEGIN
+				RETURN value::timestamptz;
+			EXCEPTION WHEN others THEN
+				RETURN NULL;
+			END;
+			$$ LANGUAGE plpgsql;
+			WITH moved AS (
+				DELETE FROM snowdepths
+				WHERE timestamp IS NOT NULL AND snowdepths_parse_timestamp(timestamp::text) IS NULL
+				RETURNING id, created_at, updated_at, deleted_at, latitude, longitude, device, depth, timestamp::text
+			)
+			INSERT INTO snowdepths_unconverted (id, created_at, updated_at, deleted_at, latitude, longitude, device, depth, timestamp, reason)
+				SELECT id, created_at, updated_at, deleted_at, latitude, longitude, device, depth, timestamp, 'unparseable' FROM moved;
+			WITH moved AS (
+				DELETE FROM snowdepths duplicate USING snowdepths original
+				WHERE duplicate.device = original.device
+					AND snowdepths_parse_timestamp(duplicate.timestamp::text) = snowdepths_parse_timestamp(original.timestamp::text)
+					AND duplicate.id > original.id
+				RETURNING duplicate.id, duplicate.created_at, duplicate.updated_at, duplicate.deleted_at, duplicate.latitude,
+					duplicate.longitude, duplicate.device, duplicate.depth, duplicate.timestamp::text
+			)
+			INSERT INTO snowdepths_unconverted (id, created_at, updated_at, deleted_at, latitude, longitude, device, depth, timestamp, reason)
+				SELECT id, created_at, updated_at, deleted_at, latitude, longitude, device, depth, timestamp, 'duplicate' FROM moved;
+			DROP FUNCTION snowdepths_parse_timestamp(text);`,
+		// Moved measurements can not be restored once the column has been converted
+		down: `DROP TABLE snowdepths_unconverted;`,
+	},
+	{
+		version:     16,
+		description: "add claimed_until column to outbox so that messages are published outside of the claiming transaction",
+		up: `
+			ALTER TABLE outbox ADD COLUMN claimed_until timestamptz;`,
+		down: `ALTER TABLE outbox DROP COLUMN claimed_until;`,
+	},
+}
+
+// ordered returns the migrations in the order they are applied, where each migration is
+// preceded by the migrations that are applied ahead of it
+func ordered() []migration {
+	result := make([]migration, 0, len(migrations))
+
+	for _, mig := range migrations {
+		if mig.before != 0 {
+			continue
+		}
+
+		for _, prep := range migrations {
+			if prep.before == mig.version {
+				result = append(result, prep)
+			}
+		}
+
+		result = append(result, mig)
+	}
+
+	return result
+}
+
+// MigrationStatus describes a known migration and when it was applied, if ever
+type MigrationStatus struct {
+	Version     uint
+	Description string
+	AppliedAt   *time.Time
+}
+
+// Migrator applies and rolls back the versioned schema migrations
+type Migrator struct {
+	impl   *gorm.DB
+	logger zerolog.Logger
+}
+
+type schemaMigration struct {
+	Version     uint `gorm:"primary_key"`
+	Description string
+	AppliedAt   time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// NewMigrator connects to the database configured through the environment and
+// returns a Migrator for it
+func NewMigrator(logger zerolog.Logger) (*Migrator, error) {
+	conn, err := openConnection(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := newMigrator(conn, logger)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
+func newMigrator(impl *gorm.DB, logger zerolog.Logger) (*Migrator, error) {
+	err := impl.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer PRIMARY KEY,
+			description text NOT NULL,
+			applied_at timestamp with time zone NOT NULL
+		)`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the schema_migrations table: %w", err)
+	}
+
+	return &Migrator{impl: impl, logger: logger}, nil
+}
+
+// Close closes the underlying database connection
+func (m *Migrator) Close() error {
+	return m.impl.Close()
+}
+
+// Status returns the status of all known migrations, ordered by version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied := []schemaMigration{}
+	if err := m.impl.Find(&applied).Error; err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[uint]time.Time{}
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		s := MigrationStatus{Version: mig.version, Description: mig.description}
+		if t, ok := appliedAt[mig.version]; ok {
+			s.AppliedAt = &t
+		}
+		status = append(status, s)
+	}
+
+	return status, nil
+}
+
+// Pending returns the number of migrations that have not yet been applied
+func (m *Migrator) Pending() (int, error) {
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies all pending migrations in order and returns the number of applied migrations
+func (m *Migrator) Up() (int, error) {
+	count := 0
+
+	for _, mig := range ordered() {
+		applied, err := m.apply(mig)
+		if err != nil {
+			return count, fmt.Errorf("migration %d (%s) failed: %w", mig.version, mig.description, err)
+		}
+
+		if applied {
+			m.logger.Info().Uint("version", mig.version).Str("description", mig.description).Msg("applied migration")
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Down rolls back the most recently applied migration and returns its version, or
+// zero if no migrations have been applied
+func (m *Migrator) Down() (uint, error) {
+	latest := schemaMigration{}
+	result := m.impl.Order("version desc").First(&latest)
+	if result.RecordNotFound() {
+		return 0, nil
+	} else if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var mig *migration
+	for idx := range migrations {
+		if migrations[idx].version == latest.Version {
+			mig = &migrations[idx]
+		}
+	}
+
+	if mig == nil {
+		return 0, fmt.Errorf("database is on unknown migration version %d", latest.Version)
+	}
+
+	err := m.impl.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(mig.down).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{Version: mig.version}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rollback of migration %d (%s) failed: %w", mig.version, mig.description, err)
+	}
+
+	m.logger.Info().Uint("version", mig.version).Str("description", mig.description).Msg("rolled back migration")
+
+	return mig.version, nil
+}
+
+// apply runs a single migration within a transaction, unless it has already been applied.
+// An advisory lock protects against several instances migrating the same database at once.
+func (m *Migrator) apply(mig migration) (bool, error) {
+	applied := false
+
+	err := m.impl.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+
+		count := 0
+		if err := tx.Model(&schemaMigration{}).Where("version = ?", mig.version).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return nil
+		}
+
+		if err := tx.Exec(mig.up).Error; err != nil {
+			return err
+		}
+
+		err := tx.Create(&schemaMigration{
+			Version:     mig.version,
+			Description: mig.description,
+			AppliedAt:   time.Now().UTC(),
+		}).Error
+
+		applied = (err == nil)
+		return err
+	})
+
+	return applied, err
+}
+
+// migrationLockID is an arbitrary key for the advisory lock that serializes migrations
+const migrationLockID = 7382194
@@ -0,0 +1,34 @@
+package database
+
+import "testing"
+
+func TestMigrationsAreAppliedInOrder(t *testing.T) {
+	position := map[uint]int{}
+	for idx, mig := range ordered() {
+		if _, ok := position[mig.version]; ok {
+			t.Fatalf("migration %d is applied more than once", mig.version)
+		}
+		position[mig.version] = idx
+	}
+
+	if len(position) != len(migrations) {
+		t.Fatalf("expected all %d migrations to be applied, got %d", len(migrations), len(position))
+	}
+
+	for idx, mig := range migrations {
+		if idx > 0 && mig.version <= migrations[idx-1].version {
+			t.Errorf("migration %d is listed after migration %d", mig.version, migrations[idx-1].version)
+		}
+
+		if mig.before != 0 {
+			if position[mig.version] > position[mig.before] {
+				t.Errorf("migration %d should be applied ahead of migration %d", mig.version, mig.before)
+			}
+			continue
+		}
+
+		if idx > 0 && migrations[idx-1].before == 0 && position[mig.version] < position[migrations[idx-1].version] {
+			t.Errorf("migration %d is applied ahead of migration %d", mig.version, migrations[idx-1].version)
+		}
+	}
+}
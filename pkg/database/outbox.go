@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/events"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// OutboxMessage is a message from the outbox that is ready to be published. It implements
+// messaging.TopicMessage and marshals to the stored body.
+type OutboxMessage struct {
+	ID       uint64
+	Attempts int
+	// Webhook is set for alerts that should be posted to the webhook of their rule instead
+	// of being published on the message bus
+	Webhook string
+
+	topic       string
+	contentType string
+	body        json.RawMessage
+}
+
+func newOutboxMessage(entry models.OutboxEntry) OutboxMessage {
+	return OutboxMessage{
+		ID:          entry.ID,
+		Attempts:    entry.Attempts,
+		Webhook:     entry.Webhook,
+		topic:       entry.Topic,
+		contentType: entry.ContentType,
+		body:        json.RawMessage(entry.Body),
+	}
+}
+
+// ContentType returns the content type of the message
+func (m OutboxMessage) ContentType() string {
+	return m.contentType
+}
+
+// TopicName returns the topic that the message should be published on
+func (m OutboxMessage) TopicName() string {
+	return m.topic
+}
+
+// MarshalJSON returns the stored body of the message
+func (m OutboxMessage) MarshalJSON() ([]byte, error) {
+	return m.body, nil
+}
+
+// OutboxResult reports what happened when messages were relayed from the outbox
+type OutboxResult struct {
+	Published int
+	// Failed is set when a message could not be published on the message bus, in which
+	// case it is retried the next time together with the messages after it
+	Failed bool
+}
+
+// topicEvent is an event that can be stored in the outbox
+type topicEvent interface {
+	ContentType() string
+	TopicName() string
+}
+
+// newOutboxEntries creates the outbox entries for events
+func newOutboxEntries(evts ...topicEvent) ([]models.OutboxEntry, error) {
+	now := time.Now().UTC()
+	entries := make([]models.OutboxEntry, 0, len(evts))
+
+	for _, evt := range evts {
+		body, err := json.Marshal(evt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, models.OutboxEntry{
+			CreatedAt:   now,
+			Topic:       evt.TopicName(),
+			ContentType: evt.ContentType(),
+			Body:        string(body),
+		})
+	}
+
+	return entries, nil
+}
+
+// storedEvents creates a SnowdepthStored event for each measurement
+func storedEvents(measurements ...*models.Snowdepth) []topicEvent {
+	evts := make([]topicEvent, 0, len(measurements))
+	for _, m := range measurements {
+		evts = append(evts, events.NewSnowdepthStored(m))
+	}
+	return evts
+}
+
+// alertEntries creates the outbox entries for alerts, which are published on the message bus
+// and also posted to the webhooks of the rules that have one
+func alertEntries(alerts []Alert) ([]models.OutboxEntry, error) {
+	evts := make([]topicEvent, 0, len(alerts))
+	webhooks := []string{}
+
+	for idx := range alerts {
+		alert := &alerts[idx]
+		evt := events.NewSnowdepthAlert(&alert.Rule, &alert.Measurement, alert.TriggeredAt)
+
+		evts = append(evts, evt)
+		webhooks = append(webhooks, "")
+
+		if alert.Rule.WebhookURL != "" {
+			evts = append(evts, evt)
+			webhooks = append(webhooks, alert.Rule.WebhookURL)
+		}
+	}
+
+	entries, err := newOutboxEntries(evts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range entries {
+		entries[idx].Webhook = webhooks[idx]
+	}
+
+	return entries, nil
+}
+
+// enqueueStored adds SnowdepthStored events for measurements to the outbox, as part of the
+// transaction that stored them, so that events are only published for committed changes
+func enqueueStored(tx *gorm.DB, measurements ...*models.Snowdepth) error {
+	if len(measurements) == 0 {
+		return nil
+	}
+
+	entries, err := newOutboxEntries(storedEvents(measurements...)...)
+	if err != nil {
+		return err
+	}
+
+	for idx := range entries {
+		if err := tx.Create(&entries[idx]).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultOutboxClaim is how long messages are claimed for when SNOWDEPTH_DB_RELAY_TIMEOUT is zero
+const defaultOutboxClaim = time.Minute
+
+// RelayOutbox publishes up to limit messages from the outbox in the order that they were
+// added, and removes the ones that were published. Relaying stops at the first message that
+// can not be published on the message bus, while a webhook that fails is retried the next
+// time without holding up the other messages.
+//
+// The messages are claimed for the relay timeout in a short transaction, so that several
+// instances can relay concurrently, and are then published without holding any locks. Each
+// message is removed, or has its attempt recorded, as soon as it has been published. Messages
+// that are not published before the claim expires, for example because the instance stops,
+// are relayed again by the next relay, so messages are delivered at least once.
+func (db *myDB) RelayOutbox(ctx context.Context, limit int, publish func(context.Context, OutboxMessage) error) (OutboxResult, error) {
+	claim := db.timeouts[opRelay]
+	if claim == 0 {
+		claim = defaultOutboxClaim
+	}
+
+	// The claim is compared when it is released, so it is kept at the precision of the database
+	claimedUntil := time.Now().UTC().Add(claim).Truncate(time.Microsecond)
+
+	entries, err := db.claimOutbox(ctx, limit, claimedUntil)
+	if err != nil {
+		return OutboxResult{}, err
+	}
+
+	// Publishing must finish while the messages are still claimed
+	publishCtx, cancel := context.WithDeadline(ctx, claimedUntil)
+	defer cancel()
+
+	result := OutboxResult{}
+
+	for idx, entry := range entries {
+		if publishCtx.Err() != nil {
+			result.Failed = true
+			return result, db.releaseOutbox(ctx, entries[idx:], claimedUntil)
+		}
+
+		if publishErr := publish(publishCtx, newOutboxMessage(entry)); publishErr != nil {
+			err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+				return tx.Model(&models.OutboxEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+					"attempts":      gorm.Expr("attempts + 1"),
+					"last_error":    publishErr.Error(),
+					"claimed_until": nil,
+				}).Error
+			})
+			if err != nil {
+				return result, err
+			}
+
+			if entry.Webhook != "" {
+				continue
+			}
+
+			result.Failed = true
+			return result, db.releaseOutbox(ctx, entries[idx+1:], claimedUntil)
+		}
+
+		err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+			return tx.Where("id = ?", entry.ID).Delete(&models.OutboxEntry{}).Error
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.Published++
+	}
+
+	return result, nil
+}
+
+// claimOutbox claims up to limit messages from the outbox that are not claimed by another
+// relay, in the order that they were added
+func (db *myDB) claimOutbox(ctx context.Context, limit int, claimedUntil time.Time) ([]models.OutboxEntry, error) {
+	entries := []models.OutboxEntry{}
+
+	err := db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		entries = []models.OutboxEntry{}
+
+		err := tx.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+			Where("claimed_until IS NULL OR claimed_until < ?", time.Now().UTC()).
+			Order("id").Limit(limit).Find(&entries).Error
+		if err != nil || len(entries) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+
+		return tx.Model(&models.OutboxEntry{}).Where("id IN (?)", ids).Update("claimed_until", claimedUntil).Error
+	})
+
+	return entries, err
+}
+
+// releaseOutbox releases the claim on messages that were not published, so that the next
+// relay does not have to wait for the claim to expire. Messages that have since been claimed
+// by another relay are left alone.
+func (db *myDB) releaseOutbox(ctx context.Context, entries []models.OutboxEntry, claimedUntil time.Time) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+
+	return db.transaction(ctx, opWrite, func(tx *gorm.DB) error {
+		return tx.Model(&models.OutboxEntry{}).
+			Where("id IN (?) AND claimed_until = ?", ids, claimedUntil).
+			Update("claimed_until", nil).Error
+	})
+}
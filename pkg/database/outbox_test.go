@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+func TestInMemoryRelayOutbox(t *testing.T) {
+	failure := errors.New("unavailable")
+
+	tests := []struct {
+		name              string
+		webhooks          []string
+		failing           map[uint64]bool
+		expectedRelayed   []uint64
+		expectedPublished int
+		expectedFailed    bool
+		expectedRemaining map[uint64]int
+	}{
+		{
+			name:              "all published",
+			webhooks:          []string{"", "", ""},
+			expectedRelayed:   []uint64{1, 2, 3},
+			expectedPublished: 3,
+			expectedRemaining: map[uint64]int{},
+		},
+		{
+			name:              "bus failure stops the relay",
+			webhooks:          []string{"", "", ""},
+			failing:           map[uint64]bool{2: true},
+			expectedRelayed:   []uint64{1, 2},
+			expectedPublished: 1,
+			expectedFailed:    true,
+			expectedRemaining: map[uint64]int{2: 1, 3: 0},
+		},
+		{
+			name:              "webhook failure does not hold up the other messages",
+			webhooks:          []string{"", "https://example.com/hook", ""},
+			failing:           map[uint64]bool{2: true},
+			expectedRelayed:   []uint64{1, 2, 3},
+			expectedPublished: 2,
+			expectedRemaining: map[uint64]int{2: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatastore(t, DuplicateIgnore, testRules).(*inMemoryDB)
+
+			entries := []models.OutboxEntry{}
+			for _, webhook := range tc.webhooks {
+				entries = append(entries, models.OutboxEntry{Topic: "test", Body: "{}", Webhook: webhook})
+			}
+			db.enqueue(entries)
+
+			relayed := []uint64{}
+			result, err := db.RelayOutbox(context.Background(), 10, func(_ context.Context, msg OutboxMessage) error {
+				relayed = append(relayed, msg.ID)
+				if tc.failing[msg.ID] {
+					return failure
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !equalIDs(relayed, tc.expectedRelayed) {
+				t.Errorf("expected messages %v to be relayed, got %v", tc.expectedRelayed, relayed)
+			}
+			if result.Published != tc.expectedPublished || result.Failed != tc.expectedFailed {
+				t.Errorf("expected %d published and failed to be %t, got %+v", tc.expectedPublished, tc.expectedFailed, result)
+			}
+
+			if len(db.outbox) != len(tc.expectedRemaining) {
+				t.Fatalf("expected %d messages to remain in the outbox, got %d", len(tc.expectedRemaining), len(db.outbox))
+			}
+			for _, entry := range db.outbox {
+				attempts, ok := tc.expectedRemaining[entry.ID]
+				if !ok || entry.Attempts != attempts {
+					t.Errorf("message %d: expected to remain with %d attempts, got %d", entry.ID, attempts, entry.Attempts)
+				}
+				if attempts > 0 && entry.LastError != failure.Error() {
+					t.Errorf("message %d: expected the last error to be recorded, got %q", entry.ID, entry.LastError)
+				}
+			}
+		})
+	}
+}
+
+func TestInMemoryRelayOutboxIsLimited(t *testing.T) {
+	db := newTestDatastore(t, DuplicateIgnore, testRules).(*inMemoryDB)
+	db.enqueue([]models.OutboxEntry{{Topic: "test"}, {Topic: "test"}, {Topic: "test"}})
+
+	publish := func(_ context.Context, msg OutboxMessage) error { return nil }
+
+	for _, expected := range []int{2, 1, 0} {
+		result, err := db.RelayOutbox(context.Background(), 2, publish)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result.Published != expected {
+			t.Errorf("expected %d messages to be published, got %d", expected, result.Published)
+		}
+	}
+}
+
+func equalIDs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// QuarantineQuery selects measurements from the quarantine by the time they were rejected.
+// Zero values do not restrict the result, and the most recently rejected are returned first.
+type QuarantineQuery struct {
+	From   time.Time
+	To     time.Time
+	Device *string
+	Limit  uint64
+}
+
+func (q QuarantineQuery) matches(m models.QuarantinedSnowdepth) bool {
+	if !q.From.IsZero() && m.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !m.CreatedAt.Before(q.To) {
+		return false
+	}
+	if q.Device != nil && m.Device != *q.Device {
+		return false
+	}
+	return true
+}
+
+func newQuarantinedSnowdepth(m *models.Snowdepth, rejection *RejectedError) *models.QuarantinedSnowdepth {
+	return &models.QuarantinedSnowdepth{
+		CreatedAt: time.Now().UTC(),
+		Device:    m.Device,
+		Latitude:  m.Latitude,
+		Longitude: m.Longitude,
+		Depth:     m.Depth,
+		RawDepth:  m.RawDepth,
+		Timestamp: m.Timestamp,
+		Rule:      rejection.Rule,
+		Reason:    rejection.Reason,
+	}
+}
+
+// GetQuarantinedSnowdepths returns the rejected measurements that match the query
+func (db *myDB) GetQuarantinedSnowdepths(ctx context.Context, query QuarantineQuery) ([]models.QuarantinedSnowdepth, error) {
+	quarantined := []models.QuarantinedSnowdepth{}
+
+	err := db.read(ctx, opRead, func(tx *gorm.DB) error {
+		if !query.From.IsZero() {
+			tx = tx.Where("created_at >= ?", query.From)
+		}
+		if !query.To.IsZero() {
+			tx = tx.Where("created_at < ?", query.To)
+		}
+		if query.Device != nil {
+			tx = tx.Where("device = ?", *query.Device)
+		}
+		if query.Limit > 0 {
+			tx = tx.Limit(query.Limit)
+		}
+
+		return tx.Order("id desc").Find(&quarantined).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return quarantined, nil
+}
@@ -0,0 +1,325 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// RetentionPolicy decides how long measurements are kept in each storage tier. Raw
+// measurements older than RawRetention are downsampled to hourly aggregates, and all
+// measurements from before the PurgeAfterSeasons most recent snow seasons are deleted.
+// A zero value disables the corresponding rule.
+type RetentionPolicy struct {
+	RawRetention      time.Duration
+	PurgeAfterSeasons int
+	Interval          time.Duration
+}
+
+// RetentionResult reports what happened when a retention policy was applied
+type RetentionResult struct {
+	// Skipped is set when another instance was already applying the policy
+	Skipped bool
+	// Downsampled is the number of raw measurements that were replaced by aggregates
+	Downsampled int64
+	// Purged is the number of raw measurements and aggregates that were deleted
+	Purged int64
+}
+
+// minRawRetention protects the last 24 hours that the latest snow depths are read from
+const minRawRetention = 48 * time.Hour
+
+// seasonStartMonth is the month in which a new snow season begins
+const seasonStartMonth = time.July
+
+// retentionLockID is an arbitrary key for the advisory lock that makes sure only one
+// instance applies the retention policy at a time
+const retentionLockID = 7382195
+
+// LoadRetentionPolicy reads the retention policy from SNOWDEPTH_RETENTION_RAW_DAYS,
+// SNOWDEPTH_RETENTION_SEASONS and SNOWDEPTH_RETENTION_INTERVAL
+func LoadRetentionPolicy() (RetentionPolicy, error) {
+	policy := RetentionPolicy{}
+
+	rawDays, err := getEnvInt("SNOWDEPTH_RETENTION_RAW_DAYS", 0)
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RawRetention = time.Duration(rawDays) * 24 * time.Hour
+	if policy.RawRetention < 0 || (policy.RawRetention > 0 && policy.RawRetention < minRawRetention) {
+		return policy, fmt.Errorf("SNOWDEPTH_RETENTION_RAW_DAYS must be 0 or at least %d", minRawRetention/(24*time.Hour))
+	}
+
+	policy.PurgeAfterSeasons, err = getEnvInt("SNOWDEPTH_RETENTION_SEASONS", 0)
+	if err != nil {
+		return policy, err
+	}
+
+	if policy.PurgeAfterSeasons < 0 {
+		return policy, fmt.Errorf("SNOWDEPTH_RETENTION_SEASONS must not be negative")
+	}
+
+	policy.Interval, err = getEnvDuration("SNOWDEPTH_RETENTION_INTERVAL", time.Hour)
+	if err != nil {
+		return policy, err
+	}
+
+	if policy.Interval <= 0 {
+		return policy, fmt.Errorf("SNOWDEPTH_RETENTION_INTERVAL must be positive")
+	}
+
+	return policy, nil
+}
+
+// Enabled reports whether the policy contains any rules
+func (p RetentionPolicy) Enabled() bool {
+	return p.RawRetention > 0 || p.PurgeAfterSeasons > 0
+}
+
+// downsampleBefore returns the time before which raw measurements should be downsampled.
+// It is aligned to a whole hour so that an hour is never split between the tiers.
+func (p RetentionPolicy) downsampleBefore(now time.Time) time.Time {
+	return now.UTC().Add(-p.RawRetention).Truncate(time.Hour)
+}
+
+// purgeBefore returns the start of the oldest snow season that should be kept
+func (p RetentionPolicy) purgeBefore(now time.Time) time.Time {
+	now = now.UTC()
+
+	year := now.Year()
+	if now.Month() < seasonStartMonth {
+		year--
+	}
+
+	return time.Date(year-(p.PurgeAfterSeasons-1), seasonStartMonth, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// downsampleStatement moves raw measurements into hourly aggregates per device and position,
+// merging with any aggregates that already exist for the same hour
+const downsampleStatement = `
+	INSERT INTO snowdepth_aggregates (device, start, latitude, longitude, min, max, sum, count, last, last_timestamp)
+	SELECT device, date_trunc('hour', timestamp AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', latitude, longitude,
+		min(depth), max(depth), sum(depth), count(*),
+		(array_agg(depth ORDER BY timestamp DESC))[1], max(timestamp)
+	FROM snowdepths
+	WHERE deleted_at IS NULL AND timestamp < ?
+	GROUP BY 1, 2, 3, 4
+	ON CONFLICT (device, start, latitude, longitude) DO UPDATE SET
+		min = least(snowdepth_aggregates.min, excluded.min),
+		max = greatest(snowdepth_aggregates.max, excluded.max),
+		sum = snowdepth_aggregates.sum + excluded.sum,
+		count = snowdepth_aggregates.count + excluded.count,
+		last = CASE WHEN excluded.last_timestamp > snowdepth_aggregates.last_timestamp
+			THEN excluded.last ELSE snowdepth_aggregates.last END,
+		last_timestamp = greatest(snowdepth_aggregates.last_timestamp, excluded.last_timestamp)`
+
+// ApplyRetention downsamples and purges measurements according to the policy. The work is
+// done in a single transaction and is skipped if another instance is already applying it.
+func (db *myDB) ApplyRetention(ctx context.Context, policy RetentionPolicy) (RetentionResult, error) {
+	result := RetentionResult{}
+	now := time.Now()
+
+	err := db.transaction(ctx, opRetention, func(tx *gorm.DB) error {
+		locked := false
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", retentionLockID).Row().Scan(&locked); err != nil {
+			return err
+		}
+
+		if !locked {
+			result.Skipped = true
+			return nil
+		}
+
+		if policy.RawRetention > 0 {
+			before := policy.downsampleBefore(now)
+
+			if err := tx.Exec(downsampleStatement, before).Error; err != nil {
+				return err
+			}
+
+			// Soft deleted measurements are not included in the aggregates, but are removed as well
+			res := tx.Unscoped().Where("timestamp < ?", before).Delete(&models.Snowdepth{})
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Downsampled = res.RowsAffected
+		}
+
+		if policy.PurgeAfterSeasons > 0 {
+			before := policy.purgeBefore(now)
+
+			res := tx.Unscoped().Where("timestamp < ?", before).Delete(&models.Snowdepth{})
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Purged = res.RowsAffected
+
+			res = tx.Where("start < ?", before).Delete(&models.SnowdepthAggregate{})
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Purged += res.RowsAffected
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return RetentionResult{}, err
+	}
+
+	return result, nil
+}
+
+// getAggregatedHistory returns the downsampled measurements that match the query
+func getAggregatedHistory(tx *gorm.DB, query SnowdepthQuery) ([]models.Snowdepth, error) {
+	tx = tx.Model(&models.SnowdepthAggregate{})
+
+	if query.Device != nil {
+		tx = tx.Where("device = ?", *query.Device)
+	}
+
+	if !query.From.IsZero() {
+		tx = tx.Where("start >= ?", query.From)
+	}
+
+	if !query.To.IsZero() {
+		tx = tx.Where("start < ?", query.To)
+	}
+
+	if query.Area != nil {
+		tx = whereWithin(tx, *query.Area)
+	}
+
+	if query.Order == SortDescending {
+		tx = tx.Order("start desc")
+	} else {
+		tx = tx.Order("start asc")
+	}
+
+	if query.Limit > 0 {
+		tx = tx.Limit(query.Limit)
+	}
+
+	aggregates := []models.SnowdepthAggregate{}
+	if err := tx.Find(&aggregates).Error; err != nil {
+		return nil, err
+	}
+
+	depths := make([]models.Snowdepth, 0, len(aggregates))
+	for _, a := range aggregates {
+		depths = append(depths, aggregateToSnowdepth(a))
+	}
+
+	return depths, nil
+}
+
+// getAggregatedStatistics computes statistics from the downsampled measurements
+func getAggregatedStatistics(tx *gorm.DB, device *string, from, to time.Time, interval AggregationInterval) ([]models.SnowdepthStatistics, error) {
+	tx = tx.Table("snowdepth_aggregates").
+		Select(
+			"device, date_trunc(?, start AT TIME ZONE 'UTC') AS start, "+
+				"min(min) AS min, max(max) AS max, sum(sum) / sum(count) AS mean, sum(count)::bigint AS count, "+
+				"(array_agg(last ORDER BY last_timestamp DESC))[1] AS last",
+			string(interval),
+		).
+		Where("start >= ? AND start < ?", from, to)
+
+	if device != nil {
+		tx = tx.Where("device = ?", *device)
+	}
+
+	// The output column start must be referred to by position, since a plain GROUP BY start
+	// would refer to the column in snowdepth_aggregates
+	stats := []models.SnowdepthStatistics{}
+	if err := tx.Group("1, 2").Order("1, 2").Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// aggregateToSnowdepth represents an hourly aggregate as a measurement of the mean depth
+// at the start of the hour
+func aggregateToSnowdepth(a models.SnowdepthAggregate) models.Snowdepth {
+	return models.Snowdepth{
+		Latitude:  a.Latitude,
+		Longitude: a.Longitude,
+		Device:    a.Device,
+		Depth:     float32(a.Sum / float64(a.Count)),
+		Timestamp: a.Start,
+	}
+}
+
+// mergeHistory combines measurements from the raw and the aggregated tier into a
+// single result with the requested order and limit. Raw measurements that are already ordered
+// and limited are sorted again, which is cheap, so that the in-memory datastore can rely on it.
+func mergeHistory(raw, aggregated []models.Snowdepth, order SortOrder, limit uint64) []models.Snowdepth {
+	depths := append(aggregated, raw...)
+
+	sort.SliceStable(depths, func(i, j int) bool {
+		if order == SortDescending {
+			return depths[i].Timestamp.After(depths[j].Timestamp)
+		}
+		return depths[i].Timestamp.Before(depths[j].Timestamp)
+	})
+
+	if limit > 0 && uint64(len(depths)) > limit {
+		depths = depths[:limit]
+	}
+
+	return depths
+}
+
+// mergeStatistics combines statistics from the raw and the aggregated tier. Buckets that
+// contain values from both tiers are merged, and the last value is then taken from the
+// raw tier since it holds the most recent measurements.
+func mergeStatistics(raw, aggregated []models.SnowdepthStatistics) []models.SnowdepthStatistics {
+	if len(aggregated) == 0 {
+		return raw
+	}
+
+	type bucketKey struct {
+		device string
+		start  time.Time
+	}
+
+	buckets := map[bucketKey]int{}
+	result := make([]models.SnowdepthStatistics, 0, len(raw)+len(aggregated))
+
+	for _, stats := range aggregated {
+		buckets[bucketKey{stats.Device, stats.Start.UTC()}] = len(result)
+		result = append(result, stats)
+	}
+
+	for _, stats := range raw {
+		idx, ok := buckets[bucketKey{stats.Device, stats.Start.UTC()}]
+		if !ok {
+			result = append(result, stats)
+			continue
+		}
+
+		merged := &result[idx]
+		merged.Mean = (merged.Mean*float64(merged.Count) + stats.Mean*float64(stats.Count)) / float64(merged.Count+stats.Count)
+		merged.Count += stats.Count
+		merged.Min = math.Min(merged.Min, stats.Min)
+		merged.Max = math.Max(merged.Max, stats.Max)
+		merged.Last = stats.Last
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Device != result[j].Device {
+			return result[i].Device < result[j].Device
+		}
+		return result[i].Start.Before(result[j].Start)
+	})
+
+	return result
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+func TestMergeHistory(t *testing.T) {
+	at := func(offset time.Duration, depth float32) models.Snowdepth {
+		return models.Snowdepth{Device: "a", Depth: depth, Timestamp: testStart.Add(offset)}
+	}
+
+	raw := []models.Snowdepth{at(2*time.Hour, 30), at(3*time.Hour, 40)}
+	aggregated := []models.Snowdepth{at(0, 10), at(time.Hour, 20)}
+
+	tests := []struct {
+		name       string
+		raw        []models.Snowdepth
+		aggregated []models.Snowdepth
+		order      SortOrder
+		limit      uint64
+		expected   []float32
+	}{
+		{"ascending", raw, aggregated, SortAscending, 0, []float32{10, 20, 30, 40}},
+		{"descending", raw, aggregated, SortDescending, 0, []float32{40, 30, 20, 10}},
+		{"ascending with limit", raw, aggregated, SortAscending, 3, []float32{10, 20, 30}},
+		{"descending with limit", raw, aggregated, SortDescending, 1, []float32{40}},
+		{"raw only", raw, nil, SortDescending, 1, []float32{40}},
+		{"aggregated only", nil, aggregated, SortDescending, 0, []float32{20, 10}},
+		{"empty", nil, nil, SortAscending, 5, []float32{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := append([]models.Snowdepth{}, tc.raw...)
+			a := append([]models.Snowdepth{}, tc.aggregated...)
+
+			if depths := depthsOf(mergeHistory(r, a, tc.order, tc.limit)); !equalDepths(depths, tc.expected) {
+				t.Errorf("expected depths %v, got %v", tc.expected, depths)
+			}
+		})
+	}
+}
+
+func TestMergeStatistics(t *testing.T) {
+	hour := testStart
+	next := testStart.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		raw        []models.SnowdepthStatistics
+		aggregated []models.SnowdepthStatistics
+		expected   []models.SnowdepthStatistics
+	}{
+		{
+			name: "separate buckets",
+			raw:  []models.SnowdepthStatistics{{Device: "a", Start: next, Min: 30, Max: 30, Mean: 30, Count: 1, Last: 30}},
+			aggregated: []models.SnowdepthStatistics{
+				{Device: "a", Start: hour, Min: 10, Max: 20, Mean: 15, Count: 2, Last: 20},
+				{Device: "b", Start: hour, Min: 5, Max: 5, Mean: 5, Count: 1, Last: 5},
+			},
+			expected: []models.SnowdepthStatistics{
+				{Device: "a", Start: hour, Min: 10, Max: 20, Mean: 15, Count: 2, Last: 20},
+				{Device: "a", Start: next, Min: 30, Max: 30, Mean: 30, Count: 1, Last: 30},
+				{Device: "b", Start: hour, Min: 5, Max: 5, Mean: 5, Count: 1, Last: 5},
+			},
+		},
+		{
+			name:       "shared bucket is merged and takes the last value from the raw tier",
+			raw:        []models.SnowdepthStatistics{{Device: "a", Start: hour, Min: 5, Max: 40, Mean: 25, Count: 2, Last: 5}},
+			aggregated: []models.SnowdepthStatistics{{Device: "a", Start: hour, Min: 10, Max: 20, Mean: 16, Count: 3, Last: 20}},
+			expected:   []models.SnowdepthStatistics{{Device: "a", Start: hour, Min: 5, Max: 40, Mean: 19.6, Count: 5, Last: 5}},
+		},
+		{
+			name:     "raw only",
+			raw:      []models.SnowdepthStatistics{{Device: "a", Start: hour, Min: 1, Max: 2, Mean: 1.5, Count: 2, Last: 2}},
+			expected: []models.SnowdepthStatistics{{Device: "a", Start: hour, Min: 1, Max: 2, Mean: 1.5, Count: 2, Last: 2}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := mergeStatistics(tc.raw, tc.aggregated)
+
+			if len(merged) != len(tc.expected) {
+				t.Fatalf("expected %d buckets, got %d: %+v", len(tc.expected), len(merged), merged)
+			}
+
+			for idx := range tc.expected {
+				if merged[idx] != tc.expected[idx] {
+					t.Errorf("bucket %d: expected %+v, got %+v", idx, tc.expected[idx], merged[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestRetentionPolicyBoundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetentionPolicy
+		now      time.Time
+		expected time.Time
+		boundary func(RetentionPolicy, time.Time) time.Time
+	}{
+		{
+			name:     "downsample aligned to the hour",
+			policy:   RetentionPolicy{RawRetention: 72 * time.Hour},
+			now:      time.Date(2022, time.March, 10, 14, 35, 0, 0, time.UTC),
+			expected: time.Date(2022, time.March, 7, 14, 0, 0, 0, time.UTC),
+			boundary: RetentionPolicy.downsampleBefore,
+		},
+		{
+			name:     "purge during the first half of a season",
+			policy:   RetentionPolicy{PurgeAfterSeasons: 2},
+			now:      time.Date(2022, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2020, time.July, 1, 0, 0, 0, 0, time.UTC),
+			boundary: RetentionPolicy.purgeBefore,
+		},
+		{
+			name:     "purge after a new season has begun",
+			policy:   RetentionPolicy{PurgeAfterSeasons: 1},
+			now:      time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC),
+			boundary: RetentionPolicy.purgeBefore,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if boundary := tc.boundary(tc.policy, tc.now); !boundary.Equal(tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, boundary)
+			}
+		})
+	}
+}
+
+func TestInMemoryRetentionMergesTiers(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDatastore(t, DuplicateIgnore, testRules)
+
+	old := time.Now().UTC().Add(-5 * 24 * time.Hour).Truncate(time.Hour)
+	recent := time.Now().UTC().Add(-time.Hour)
+
+	device := "a"
+	add := func(when time.Time, depth float64) {
+		t.Helper()
+		if _, _, err := db.AddSnowdepthMeasurement(ctx, &device, 62.39, 17.30, depth, when.Format(time.RFC3339)); err != nil {
+			t.Fatalf("failed to add measurement: %s", err)
+		}
+	}
+
+	add(old, 10)
+	add(old.Add(10*time.Minute), 20)
+	add(old.Add(20*time.Minute), 60)
+	add(recent, 50)
+
+	result, err := db.ApplyRetention(ctx, RetentionPolicy{RawRetention: 72 * time.Hour})
+	if err != nil {
+		t.Fatalf("failed to apply retention: %s", err)
+	}
+	if result.Downsampled != 3 {
+		t.Errorf("expected 3 measurements to be downsampled, got %d", result.Downsampled)
+	}
+
+	history, err := db.GetSnowdepthHistory(ctx, SnowdepthQuery{Device: &device, Order: SortDescending})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if depths := depthsOf(history); !equalDepths(depths, []float32{50, 30}) {
+		t.Errorf("expected the recent measurement and the mean of the downsampled hour, got %v", depths)
+	}
+	if len(history) == 2 && !history[1].Timestamp.Equal(old) {
+		t.Errorf("expected the aggregate to be placed at the start of the hour %s, got %s", old, history[1].Timestamp)
+	}
+
+	stats, err := db.GetSnowdepthStatistics(ctx, &device, old, time.Time{}, AggregateHourly)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(stats) != 2 || stats[0].Count != 3 || stats[0].Min != 10 || stats[0].Max != 60 || stats[0].Last != 60 {
+		t.Errorf("expected the downsampled hour to keep its statistics, got %+v", stats)
+	}
+}
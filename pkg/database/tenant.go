@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"net/http"
+)
+
+// TenantHeader is the NGSI-LD header that identifies the tenant of a request
+const TenantHeader = "NGSILD-Tenant"
+
+var tenantCtxKey = &databaseContextKey{"tenant"}
+
+// WithTenant returns a copy of ctx that carries the tenant on whose behalf the datastore is used
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey, tenant)
+}
+
+// TenantFromContext returns the tenant in ctx, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantCtxKey).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantMiddleware packs the tenant from the NGSILD-Tenant header, if any, into context
+func TenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tenant := r.Header.Get(TenantHeader); tenant != "" {
+			r = r.WithContext(WithTenant(r.Context(), tenant))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
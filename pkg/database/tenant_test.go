@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+		found    bool
+	}{
+		{"no tenant", "", "", false},
+		{"tenant", "skelleftea", "skelleftea", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/ngsi-ld/v1/entities", nil)
+			if tc.header != "" {
+				req.Header.Set(TenantHeader, tc.header)
+			}
+
+			var tenant string
+			var found bool
+			TenantMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenant, found = TenantFromContext(r.Context())
+			})).ServeHTTP(httptest.NewRecorder(), req)
+
+			if tenant != tc.expected || found != tc.found {
+				t.Errorf("expected tenant %q (%t), got %q (%t)", tc.expected, tc.found, tenant, found)
+			}
+		})
+	}
+}
+
+func TestTenantFromContextIgnoresAnEmptyTenant(t *testing.T) {
+	if _, ok := TenantFromContext(WithTenant(context.Background(), "")); ok {
+		t.Errorf("expected an empty tenant to be ignored")
+	}
+}
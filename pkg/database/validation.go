@@ -0,0 +1,309 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// Names of the validation rules that a measurement can be rejected by
+const (
+	RuleDepthRange   = "depth-range"
+	RuleRateOfChange = "rate-of-change"
+	RulePosition     = "position"
+	RuleGeofence     = "geofence"
+	RuleFuture       = "future-timestamp"
+)
+
+// ValidationRules are the plausibility checks that new measurements must pass to be stored.
+// Measurements that fail a check are quarantined for review instead.
+type ValidationRules struct {
+	MinDepth float64
+	MaxDepth float64
+	// MaxChangePerHour is the largest difference in depth per hour, compared with the previous
+	// measurement from the same device. Zero disables the check.
+	MaxChangePerHour float64
+	// Fence limits the positions of measurements. A nil fence accepts any position.
+	Fence *Area
+	// MaxFutureSkew is how far into the future a timestamp may be, to allow for clock drift
+	MaxFutureSkew time.Duration
+}
+
+// LoadValidationRules reads the validation rules from SNOWDEPTH_VALIDATION_MIN_DEPTH,
+// SNOWDEPTH_VALIDATION_MAX_DEPTH, SNOWDEPTH_VALIDATION_MAX_CHANGE_PER_HOUR,
+// SNOWDEPTH_VALIDATION_FENCE and SNOWDEPTH_VALIDATION_MAX_FUTURE_SKEW
+func LoadValidationRules() (ValidationRules, error) {
+	rules := ValidationRules{}
+	var err error
+
+	if rules.MinDepth, err = getEnvFloat("SNOWDEPTH_VALIDATION_MIN_DEPTH", 0); err != nil {
+		return rules, err
+	}
+
+	if rules.MaxDepth, err = getEnvFloat("SNOWDEPTH_VALIDATION_MAX_DEPTH", 1000); err != nil {
+		return rules, err
+	}
+
+	if rules.MaxDepth < rules.MinDepth {
+		return rules, fmt.Errorf("SNOWDEPTH_VALIDATION_MAX_DEPTH must not be less than SNOWDEPTH_VALIDATION_MIN_DEPTH")
+	}
+
+	if rules.MaxChangePerHour, err = getEnvFloat("SNOWDEPTH_VALIDATION_MAX_CHANGE_PER_HOUR", 0); err != nil {
+		return rules, err
+	}
+
+	if rules.MaxChangePerHour < 0 {
+		return rules, fmt.Errorf("SNOWDEPTH_VALIDATION_MAX_CHANGE_PER_HOUR must not be negative")
+	}
+
+	if value := os.Getenv("SNOWDEPTH_VALIDATION_FENCE"); value != "" {
+		if rules.Fence, err = parseFence(value); err != nil {
+			return rules, err
+		}
+	}
+
+	if rules.MaxFutureSkew, err = getEnvDuration("SNOWDEPTH_VALIDATION_MAX_FUTURE_SKEW", 5*time.Minute); err != nil {
+		return rules, err
+	}
+
+	if rules.MaxFutureSkew < 0 {
+		return rules, fmt.Errorf("SNOWDEPTH_VALIDATION_MAX_FUTURE_SKEW must not be negative")
+	}
+
+	return rules, nil
+}
+
+// parseFence parses a bounding box given as south,west,north,east
+func parseFence(value string) (*Area, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("SNOWDEPTH_VALIDATION_FENCE must be given as south,west,north,east")
+	}
+
+	coords := make([]float64, 4)
+	for idx, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q in SNOWDEPTH_VALIDATION_FENCE: %w", part, err)
+		}
+		coords[idx] = f
+	}
+
+	fence := &Area{Box: &BoundingBox{South: coords[0], West: coords[1], North: coords[2], East: coords[3]}}
+	if err := fence.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid SNOWDEPTH_VALIDATION_FENCE: %w", err)
+	}
+
+	return fence, nil
+}
+
+func getEnvFloat(key string, fallback float64) (float64, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+
+	return f, nil
+}
+
+// RejectedError is returned when a measurement fails one of the validation rules and has
+// been quarantined
+type RejectedError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("measurement rejected by the %s rule and quarantined: %s", e.Rule, e.Reason)
+}
+
+// Is makes a RejectedError match ErrValidation
+func (e *RejectedError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func reject(rule, format string, args ...interface{}) *RejectedError {
+	return &RejectedError{Rule: rule, Reason: fmt.Sprintf(format, args...)}
+}
+
+// checkValues applies the rules that only depend on the values of the measurement itself
+func (r ValidationRules) checkValues(m *models.Snowdepth) *RejectedError {
+	depth := float64(m.Depth)
+	if math.IsNaN(depth) || depth < r.MinDepth || depth > r.MaxDepth {
+		return reject(RuleDepthRange, "depth %.1f is outside of the range %.1f to %.1f", depth, r.MinDepth, r.MaxDepth)
+	}
+
+	if !isValidPosition(m.Latitude, m.Longitude) || (m.Latitude == 0 && m.Longitude == 0) {
+		return reject(RulePosition, "(%f, %f) is not a valid position", m.Latitude, m.Longitude)
+	}
+
+	if r.Fence != nil && !r.Fence.Contains(m.Latitude, m.Longitude) {
+		return reject(RuleGeofence, "(%f, %f) is outside of the geographic fence", m.Latitude, m.Longitude)
+	}
+
+	return nil
+}
+
+// checkCorrection applies the rules that only depend on the values of a corrected measurement.
+// Corrections that fail are refused rather than quarantined.
+func (r ValidationRules) checkCorrection(m *models.Snowdepth) error {
+	if rejection := r.checkValues(m); rejection != nil {
+		return newError(ErrValidation, "the corrected measurement fails the %s rule: %s", rejection.Rule, rejection.Reason)
+	}
+	return nil
+}
+
+// check applies all rules to a new measurement. The previous measurement from the same
+// device is used to check the rate of change, and may be nil.
+func (r ValidationRules) check(m, previous *models.Snowdepth, now time.Time) *RejectedError {
+	if rejection := r.checkValues(m); rejection != nil {
+		return rejection
+	}
+
+	if m.Timestamp.After(now.Add(r.MaxFutureSkew)) {
+		return reject(RuleFuture, "timestamp %s is in the future", m.Timestamp.Format(time.RFC3339))
+	}
+
+	if r.MaxChangePerHour > 0 && previous != nil {
+		// Changes are compared over at least an hour, so that closely spaced measurements are
+		// not rejected for small differences
+		hours := math.Max(m.Timestamp.Sub(previous.Timestamp).Hours(), 1)
+		change := math.Abs(float64(m.Depth - previous.Depth))
+
+		if change > r.MaxChangePerHour*hours {
+			return reject(
+				RuleRateOfChange, "depth changed by %.1f since %s, which is more than %.1f per hour",
+				change, previous.Timestamp.Format(time.RFC3339), r.MaxChangePerHour,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateMeasurements applies the rules to new measurements and returns the rejection for
+// each of them, or nil if it was accepted. The rate of change of a measurement is compared
+// with the stored measurement that precedes it, or with an accepted measurement earlier in
+// the same batch.
+func (r ValidationRules) validateMeasurements(measurements []*models.Snowdepth, previous func(m *models.Snowdepth) *models.Snowdepth) []*RejectedError {
+	now := time.Now().UTC()
+	rejections := make([]*RejectedError, len(measurements))
+
+	order := make([]int, len(measurements))
+	for idx := range order {
+		order[idx] = idx
+	}
+
+	// Check the measurements from each device in chronological order
+	sort.SliceStable(order, func(a, b int) bool {
+		ma, mb := measurements[order[a]], measurements[order[b]]
+		if ma.Device != mb.Device {
+			return ma.Device < mb.Device
+		}
+		return ma.Timestamp.Before(mb.Timestamp)
+	})
+
+	accepted := map[string]*models.Snowdepth{}
+
+	for _, idx := range order {
+		m := measurements[idx]
+
+		var prev *models.Snowdepth
+		if m.Device != "" && r.MaxChangePerHour > 0 {
+			prev = previous(m)
+			if last, ok := accepted[m.Device]; ok && last.Timestamp.Before(m.Timestamp) {
+				if prev == nil || last.Timestamp.After(prev.Timestamp) {
+					prev = last
+				}
+			}
+		}
+
+		rejections[idx] = r.check(m, prev, now)
+		if rejections[idx] == nil {
+			accepted[m.Device] = m
+		}
+	}
+
+	return rejections
+}
+
+// previousMeasurements fetches the stored measurement that precedes each of the measurements
+// from the same device, in a single statement
+func previousMeasurements(tx *gorm.DB, measurements []*models.Snowdepth) (map[measurementKey]*models.Snowdepth, error) {
+	values := []string{}
+	args := []interface{}{}
+
+	for _, m := range measurements {
+		if m.Device != "" {
+			values = append(values, "(?::text, ?::timestamptz)")
+			args = append(args, m.Device, m.Timestamp)
+		}
+	}
+
+	previous := map[measurementKey]*models.Snowdepth{}
+	if len(values) == 0 {
+		return previous, nil
+	}
+
+	rows := []struct {
+		Before time.Time
+		models.Snowdepth
+	}{}
+
+	err := tx.Raw(`
+		SELECT k.before, s.* FROM (VALUES `+strings.Join(values, ", ")+`) AS k(device, before)
+		CROSS JOIN LATERAL (
+			SELECT * FROM snowdepths
+			WHERE device = k.device AND timestamp < k.before AND deleted_at IS NULL
+			ORDER BY timestamp DESC LIMIT 1
+		) AS s`, args...).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range rows {
+		previous[keyOf(rows[idx].Device, rows[idx].Before)] = &rows[idx].Snowdepth
+	}
+
+	return previous, nil
+}
+
+// validate applies the rules to new measurements within a transaction, and stores the
+// rejected measurements in the quarantine table
+func (db *myDB) validate(tx *gorm.DB, measurements []*models.Snowdepth) ([]*RejectedError, error) {
+	var previous map[measurementKey]*models.Snowdepth
+
+	if db.rules.MaxChangePerHour > 0 {
+		var err error
+		if previous, err = previousMeasurements(tx, measurements); err != nil {
+			return nil, err
+		}
+	}
+
+	rejections := db.rules.validateMeasurements(measurements, func(m *models.Snowdepth) *models.Snowdepth {
+		return previous[keyOf(m.Device, m.Timestamp)]
+	})
+
+	for idx, rejection := range rejections {
+		if rejection != nil {
+			if err := tx.Create(newQuarantinedSnowdepth(measurements[idx], rejection)).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return rejections, nil
+}
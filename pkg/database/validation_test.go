@@ -0,0 +1,127 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+func TestLoadValidationRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected ValidationRules
+		fence    bool
+		fails    bool
+	}{
+		{
+			name:     "defaults",
+			expected: ValidationRules{MinDepth: 0, MaxDepth: 1000, MaxFutureSkew: 5 * time.Minute},
+		},
+		{
+			name: "configured",
+			env: map[string]string{
+				"SNOWDEPTH_VALIDATION_MIN_DEPTH": "5", "SNOWDEPTH_VALIDATION_MAX_DEPTH": "300",
+				"SNOWDEPTH_VALIDATION_MAX_CHANGE_PER_HOUR": "10", "SNOWDEPTH_VALIDATION_MAX_FUTURE_SKEW": "1m",
+				"SNOWDEPTH_VALIDATION_FENCE": "55, 10, 70, 25",
+			},
+			expected: ValidationRules{MinDepth: 5, MaxDepth: 300, MaxChangePerHour: 10, MaxFutureSkew: time.Minute},
+			fence:    true,
+		},
+		{name: "invalid depth", env: map[string]string{"SNOWDEPTH_VALIDATION_MAX_DEPTH": "deep"}, fails: true},
+		{name: "max below min", env: map[string]string{"SNOWDEPTH_VALIDATION_MIN_DEPTH": "10", "SNOWDEPTH_VALIDATION_MAX_DEPTH": "5"}, fails: true},
+		{name: "negative change", env: map[string]string{"SNOWDEPTH_VALIDATION_MAX_CHANGE_PER_HOUR": "-1"}, fails: true},
+		{name: "negative skew", env: map[string]string{"SNOWDEPTH_VALIDATION_MAX_FUTURE_SKEW": "-1m"}, fails: true},
+		{name: "fence with three coordinates", env: map[string]string{"SNOWDEPTH_VALIDATION_FENCE": "55,10,70"}, fails: true},
+		{name: "fence with south above north", env: map[string]string{"SNOWDEPTH_VALIDATION_FENCE": "70,10,55,25"}, fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			rules, err := LoadValidationRules()
+
+			if tc.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", rules)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if (rules.Fence != nil) != tc.fence {
+				t.Errorf("expected a fence to be %t, got %+v", tc.fence, rules.Fence)
+			}
+
+			rules.Fence = nil
+			if rules != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, rules)
+			}
+		})
+	}
+}
+
+func TestValidationRulesCheck(t *testing.T) {
+	rules := ValidationRules{MinDepth: 0, MaxDepth: 300, MaxChangePerHour: 10, MaxFutureSkew: 5 * time.Minute}
+	now := testStart.Add(24 * time.Hour)
+
+	previous := &models.Snowdepth{Device: "a", Latitude: 62.39, Longitude: 17.30, Depth: 20, Timestamp: testStart}
+
+	tests := []struct {
+		name         string
+		depth        float32
+		offset       time.Duration
+		previous     *models.Snowdepth
+		expectedRule string
+	}{
+		{"depth at the upper limit", 300, time.Hour, nil, ""},
+		{"change at the limit", 30, time.Hour, previous, ""},
+		{"change above the limit", 30.5, time.Hour, previous, RuleRateOfChange},
+		{"change within minutes is compared over an hour", 29, 10 * time.Minute, previous, ""},
+		{"change over hours", 50, 3 * time.Hour, previous, ""},
+		{"no previous measurement", 250, time.Hour, nil, ""},
+		{"within the clock skew", 20, 24*time.Hour + 4*time.Minute, nil, ""},
+		{"beyond the clock skew", 20, 24*time.Hour + 6*time.Minute, nil, RuleFuture},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &models.Snowdepth{Device: "a", Latitude: 62.39, Longitude: 17.30, Depth: tc.depth, Timestamp: testStart.Add(tc.offset)}
+
+			rejection := rules.check(m, tc.previous, now)
+			if tc.expectedRule == "" && rejection != nil {
+				t.Errorf("expected the measurement to be accepted, got %s", rejection)
+			} else if tc.expectedRule != "" && (rejection == nil || rejection.Rule != tc.expectedRule) {
+				t.Errorf("expected the %s rule to reject the measurement, got %v", tc.expectedRule, rejection)
+			}
+		})
+	}
+}
+
+func TestValidationRulesCheckCorrection(t *testing.T) {
+	rules := ValidationRules{MinDepth: 0, MaxDepth: 300, MaxChangePerHour: 1}
+
+	// The rate of change and the timestamp are not checked for corrections
+	accepted := &models.Snowdepth{Latitude: 62.39, Longitude: 17.30, Depth: 250, Timestamp: time.Now().Add(time.Hour)}
+	if err := rules.checkCorrection(accepted); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	refused := &models.Snowdepth{Latitude: 62.39, Longitude: 17.30, Depth: 301}
+	err := rules.checkCorrection(refused)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+
+	rejection := &RejectedError{}
+	if errors.As(err, &rejection) {
+		t.Errorf("expected the correction to be refused rather than quarantined")
+	}
+}
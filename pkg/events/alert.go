@@ -0,0 +1,58 @@
+package events
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+const (
+	// SnowdepthAlertTopic is the topic that SnowdepthAlert events are published on
+	SnowdepthAlertTopic = "snowdepth-alert"
+	// SnowdepthAlertContentType is the content type of SnowdepthAlert events, which predate
+	// the versioned content types
+	SnowdepthAlertContentType = "application/json"
+)
+
+// SnowdepthAlert is published when a measurement makes the depth cross the threshold of an
+// alert rule, and is also the body of the requests to the webhook of the rule
+type SnowdepthAlert struct {
+	RuleID    string  `json:"ruleID"`
+	RuleName  string  `json:"ruleName"`
+	Direction string  `json:"direction"`
+	Threshold float64 `json:"threshold"`
+	// Device is empty for manually added measurements
+	Device      string    `json:"device,omitempty"`
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Depth       float64   `json:"depth"`
+	ObservedAt  time.Time `json:"observedAt"`
+	TriggeredAt time.Time `json:"triggeredAt"`
+}
+
+// NewSnowdepthAlert creates the event for a rule that was triggered by a measurement
+func NewSnowdepthAlert(rule *models.AlertRule, m *models.Snowdepth, triggeredAt time.Time) SnowdepthAlert {
+	return SnowdepthAlert{
+		RuleID:      strconv.FormatUint(uint64(rule.ID), 10),
+		RuleName:    rule.Name,
+		Direction:   rule.Direction,
+		Threshold:   rule.Threshold,
+		Device:      m.Device,
+		Latitude:    m.Latitude,
+		Longitude:   m.Longitude,
+		Depth:       float64(m.Depth),
+		ObservedAt:  m.Timestamp.UTC(),
+		TriggeredAt: triggeredAt.UTC(),
+	}
+}
+
+// ContentType returns the content type of the event
+func (e SnowdepthAlert) ContentType() string {
+	return SnowdepthAlertContentType
+}
+
+// TopicName returns the topic that the event is published on
+func (e SnowdepthAlert) TopicName() string {
+	return SnowdepthAlertTopic
+}
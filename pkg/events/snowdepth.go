@@ -0,0 +1,69 @@
+// Package events contains the public events that the service publishes on the message bus.
+// The payload of an event type may only change in backwards compatible ways, a breaking change
+// requires a new version with its own content type.
+package events
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+const (
+	// SnowdepthStoredTopic is the topic that SnowdepthStored events are published on
+	SnowdepthStoredTopic = "snowdepth-stored"
+	// SnowdepthStoredVersion is the current version of the SnowdepthStored event
+	SnowdepthStoredVersion = 1
+	// SnowdepthStoredContentType identifies version 1 of the SnowdepthStored event
+	SnowdepthStoredContentType = "application/vnd.diwise.snowdepth-stored.v1+json"
+)
+
+// Position is a WGS84 position
+type Position struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// SnowdepthStored is published when a measurement has been stored, either as a new
+// measurement or by overwriting a stored measurement with the same device and timestamp.
+// Events are delivered at least once, and consumers can use the measurement id together with
+// storedAt to detect redeliveries.
+type SnowdepthStored struct {
+	Version int    `json:"version"`
+	ID      string `json:"id"`
+	// Device is empty for manually added measurements
+	Device   string   `json:"device,omitempty"`
+	Position Position `json:"position"`
+	// RawDepth is the value that was reported, and Depth is the value after calibration
+	RawDepth  float64   `json:"rawDepth"`
+	Depth     float64   `json:"depth"`
+	Timestamp time.Time `json:"timestamp"`
+	Manual    bool      `json:"manual"`
+	StoredAt  time.Time `json:"storedAt"`
+}
+
+// NewSnowdepthStored creates the event for a stored measurement
+func NewSnowdepthStored(m *models.Snowdepth) SnowdepthStored {
+	return SnowdepthStored{
+		Version:   SnowdepthStoredVersion,
+		ID:        strconv.FormatUint(uint64(m.ID), 10),
+		Device:    m.Device,
+		Position:  Position{Latitude: m.Latitude, Longitude: m.Longitude},
+		RawDepth:  float64(m.RawDepth),
+		Depth:     float64(m.Depth),
+		Timestamp: m.Timestamp.UTC(),
+		Manual:    m.Device == "",
+		StoredAt:  m.UpdatedAt.UTC(),
+	}
+}
+
+// ContentType returns the versioned content type of the event
+func (e SnowdepthStored) ContentType() string {
+	return SnowdepthStoredContentType
+}
+
+// TopicName returns the topic that the event is published on
+func (e SnowdepthStored) TopicName() string {
+	return SnowdepthStoredTopic
+}
@@ -2,21 +2,29 @@ package handler
 
 import (
 	"compress/flate"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	gql "github.com/diwise/api-snowdepth/internal/pkg/graphql"
+	"github.com/diwise/api-snowdepth/pkg/alerts"
+	"github.com/diwise/api-snowdepth/pkg/auth"
 	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/metrics"
 	"github.com/diwise/api-snowdepth/pkg/models"
+	"github.com/diwise/api-snowdepth/pkg/probes"
+	"github.com/diwise/api-snowdepth/pkg/tracing"
 	"github.com/diwise/messaging-golang/pkg/messaging"
 	"github.com/diwise/ngsi-ld-golang/pkg/datamodels/diwise"
 	"github.com/diwise/ngsi-ld-golang/pkg/datamodels/fiware"
@@ -28,6 +36,7 @@ import (
 	"github.com/go-chi/httplog"
 	"github.com/rs/cors"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // RequestRouter wraps the concrete router implementation
@@ -35,24 +44,27 @@ type RequestRouter struct {
 	impl *chi.Mux
 }
 
-func (router *RequestRouter) addGraphQLHandlers(db database.Datastore) {
-	gqlServer := handler.New(gql.NewExecutableSchema(gql.Config{Resolvers: &gql.Resolver{}}))
+func (router *RequestRouter) addGraphQLHandlers(db database.Datastore, alertConfig alerts.Config) {
+	gqlServer := handler.New(gql.NewExecutableSchema(gql.Config{Resolvers: &gql.Resolver{Alerts: alertConfig}}))
 	gqlServer.AddTransport(&transport.POST{})
 	gqlServer.Use(extension.Introspection{})
+	gqlServer.Use(metrics.GraphQLExtension{})
+	gqlServer.Use(tracing.GraphQLExtension{})
+	gqlServer.SetErrorPresenter(gql.ErrorPresenter)
 
 	// TODO: Investigate some way to use closures instead of context even for GraphQL handlers
 	router.impl.Use(database.Middleware(db))
 
 	router.impl.Handle("/api/graphql/playground", playground.Handler("GraphQL playground", "/api/graphql"))
-	router.impl.Handle("/api/graphql", gqlServer)
+	router.impl.Handle("/api/graphql", otelhttp.NewHandler(gqlServer, "/api/graphql"))
 }
 
 func (router *RequestRouter) addNGSIHandlers(contextRegistry ngsi.ContextRegistry, mq messaging.MsgContext, logger zerolog.Logger) {
-	router.Get("/ngsi-ld/v1/entities", ngsi.NewQueryEntitiesHandler(contextRegistry))
-	router.Get("/ngsi-ld/v1/entities/{entity}", ngsi.NewRetrieveEntityHandler(contextRegistry))
+	router.Get("/ngsi-ld/v1/entities", ngsiHandler("queryEntities", polygonMiddleware(problemMiddleware(ngsi.NewQueryEntitiesHandler(contextRegistry)))))
+	router.Get("/ngsi-ld/v1/entities/{entity}", ngsiHandler("retrieveEntity", ngsi.NewRetrieveEntityHandler(contextRegistry)))
 	router.Post(
 		"/ngsi-ld/v1/entities",
-		ngsi.NewCreateEntityHandlerWithCallback(
+		ngsiHandler("createEntity", ngsi.NewCreateEntityHandlerWithCallback(
 			contextRegistry,
 			logger,
 			func(entityType, entityID string, request ngsi.Request, sublog zerolog.Logger) {
@@ -76,11 +88,11 @@ func (router *RequestRouter) addNGSIHandlers(contextRegistry ngsi.ContextRegistr
 				}
 
 				sublog.Info().Str("body", ecm.Body).Msg("posted an entity created event")
-			}))
+			})))
 
 	router.Patch(
 		"/ngsi-ld/v1/entities/{entity}/attrs/",
-		ngsi.NewUpdateEntityAttributesHandlerWithCallback(
+		ngsiHandler("updateEntityAttributes", ngsi.NewUpdateEntityAttributesHandlerWithCallback(
 			contextRegistry,
 			logger,
 			func(entityType, entityID string, request ngsi.Request, sublog zerolog.Logger) {
@@ -104,13 +116,52 @@ func (router *RequestRouter) addNGSIHandlers(contextRegistry ngsi.ContextRegistr
 				}
 
 				sublog.Info().Str("body", eum.Body).Msg("posted an entity updated event")
-			}))
+			})))
 }
 
-func (router *RequestRouter) addProbeHandlers() {
-	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+// ngsiHandler counts the requests that are served by an NGSI-LD handler, and traces them
+// as part of the trace context in the request headers, if any
+func ngsiHandler(name string, next http.Handler) http.HandlerFunc {
+	return otelhttp.NewHandler(metrics.NGSIHandler(name, next), name).ServeHTTP
+}
+
+func (router *RequestRouter) addProbeHandlers(readiness probes.Config, checks []probes.Check) {
+	router.Get("/healthz/live", probes.LivenessHandler())
+	router.Get("/healthz/ready", probes.ReadinessHandler(readiness, checks))
+	// Kept for probes that have not been moved to /healthz/live yet
+	router.Get("/health", probes.LivenessHandler())
+}
+
+// remoteContextSources lists the names of the remote context sources together with the
+// environment variables that hold their urls
+var remoteContextSources = []struct{ name, env string }{
+	{"pointofinterest", "NGSI_CTX_SRC_POINTOFINTEREST"},
+	{"problemreport", "NGSI_CTX_SRC_PROBLEMREPORT"},
+	{"temperature", "NGSI_CTX_SRC_TEMPERATURE"},
+	{"transportation", "NGSI_CTX_SRC_TRANSPORTATION"},
+	{"devices", "NGSI_CTX_SRC_DEVICES"},
+	{"smartwater", "NGSI_CTX_SRC_SMARTWATER"},
+	{"environment", "NGSI_CTX_SRC_ENVIRONMENT"},
+}
+
+// readinessChecks returns the checks of the datastore and, if enabled, of the configured
+// remote context sources, followed by the checks in the configuration
+func readinessChecks(db database.Datastore, readiness probes.Config) []probes.Check {
+	checks := []probes.Check{{Name: "database", Run: db.Ping}}
+
+	if readiness.CheckContextSources {
+		// The requests are also bound to the deadline of the probe, but a client without a
+		// timeout would leave them hanging if a check is ever run without one
+		client := &http.Client{Timeout: readiness.Timeout}
+
+		for _, source := range remoteContextSources {
+			if url := os.Getenv(source.env); url != "" {
+				checks = append(checks, probes.HTTPCheck("contextSource:"+source.name, url, client))
+			}
+		}
+	}
+
+	return append(checks, readiness.Checks...)
 }
 
 // Get accepts a pattern that should be routed to the handlerFn on a GET request
@@ -129,7 +180,12 @@ func (router *RequestRouter) Post(pattern string, handlerFn http.HandlerFunc) {
 }
 
 // newRequestRouter creates and returns a new router wrapper
-func newRequestRouter() *RequestRouter {
+func newRequestRouter() (*RequestRouter, error) {
+	apiKeys, err := newApiKeyMiddleware()
+	if err != nil {
+		return nil, err
+	}
+
 	router := &RequestRouter{impl: chi.NewRouter()}
 
 	router.impl.Use(cors.New(cors.Options{
@@ -140,7 +196,8 @@ func newRequestRouter() *RequestRouter {
 
 	// Enable gzip compression for ngsi-ld responses
 	compressor := middleware.NewCompressor(flate.DefaultCompression, "application/json", "application/ld+json", "application/geo+json")
-	router.impl.Use(newApiKeyMiddleware().Handler)
+	router.impl.Use(apiKeys.Handler)
+	router.impl.Use(database.TenantMiddleware)
 	router.impl.Use(compressor.Handler)
 
 	logger := httplog.NewLogger("api-snowdepth", httplog.Options{
@@ -148,21 +205,27 @@ func newRequestRouter() *RequestRouter {
 	})
 	router.impl.Use(httplog.RequestLogger(logger))
 
-	return router
+	return router, nil
 }
 
-func createRequestRouter(contextRegistry ngsi.ContextRegistry, db database.Datastore, mq messaging.MsgContext, logger zerolog.Logger) *RequestRouter {
-	router := newRequestRouter()
+func createRequestRouter(contextRegistry ngsi.ContextRegistry, db database.Datastore, mq messaging.MsgContext, alertConfig alerts.Config, readiness probes.Config, logger zerolog.Logger) (*RequestRouter, error) {
+	router, err := newRequestRouter()
+	if err != nil {
+		return nil, err
+	}
 
-	router.addGraphQLHandlers(db)
+	router.addGraphQLHandlers(db, alertConfig)
 	router.addNGSIHandlers(contextRegistry, mq, logger)
-	router.addProbeHandlers()
+	router.addProbeHandlers(readiness, readinessChecks(db, readiness))
+	router.Get("/metrics", metrics.Handler().ServeHTTP)
 
-	return router
+	return router, nil
 }
 
-// CreateRouterAndStartServing creates a request router, registers all handlers and starts serving requests
-func CreateRouterAndStartServing(db database.Datastore, mq messaging.MsgContext, logger zerolog.Logger) {
+// CreateServer creates a request router, registers all handlers and returns a server that
+// listens on SNOWDEPTH_API_PORT. The caller starts the server and shuts it down. An error is
+// returned if the api keys are not configured correctly.
+func CreateServer(db database.Datastore, mq messaging.MsgContext, alertConfig alerts.Config, readiness probes.Config, logger zerolog.Logger) (*http.Server, error) {
 
 	contextRegistry := ngsi.NewContextRegistry()
 	ctxSource := contextSource{db: db}
@@ -171,58 +234,58 @@ func CreateRouterAndStartServing(db database.Datastore, mq messaging.MsgContext,
 	remoteURL := os.Getenv("NGSI_CTX_SRC_POINTOFINTEREST")
 	regex := "^urn:ngsi-ld:Beach:.+"
 	registration, _ := ngsi.NewCsourceRegistration(fiware.BeachTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ := ngsi.NewRemoteContextSource(registration)
+	contextSource, _ := newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	regex = "^urn:ngsi-ld:ExerciseTrail:.+"
 	registration, _ = ngsi.NewCsourceRegistration(diwise.ExerciseTrailTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_PROBLEMREPORT")
 	registration, _ = ngsi.NewCsourceRegistration(fiware.Open311ServiceRequestTypeName, []string{"service_code"}, remoteURL, nil)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_TEMPERATURE")
 	registration, _ = ngsi.NewCsourceRegistration(fiware.WeatherObservedTypeName, []string{"temperature"}, remoteURL, nil)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	registration, _ = ngsi.NewCsourceRegistration(fiware.WaterQualityObservedTypeName, []string{"temperature"}, remoteURL, nil)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_TRANSPORTATION")
 	regex = "^urn:ngsi-ld:Road:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.RoadTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	regex = "^urn:ngsi-ld:RoadSegment:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.RoadSegmentTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	regex = "^urn:ngsi-ld:RoadSurfaceObserved:.+"
 	registration, _ = ngsi.NewCsourceRegistration(diwise.RoadSurfaceObservedTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	regex = "^urn:ngsi-ld:TrafficFlowObserved:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.TrafficFlowObservedTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_DEVICES")
 	regex = "^urn:ngsi-ld:Device:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.DeviceTypeName, []string{"value"}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	regex = "^urn:ngsi-ld:DeviceModel:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.DeviceModelTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_SMARTWATER")
@@ -231,28 +294,29 @@ func CreateRouterAndStartServing(db database.Datastore, mq messaging.MsgContext,
 		fiware.WaterConsumptionObservedTypeName,
 		[]string{}, remoteURL, &regex,
 	)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
 	remoteURL = os.Getenv("NGSI_CTX_SRC_ENVIRONMENT")
 	regex = "^urn:ngsi-ld:AirQualityObserved:.+"
 	registration, _ = ngsi.NewCsourceRegistration(fiware.AirQualityObservedTypeName, []string{}, remoteURL, &regex)
-	contextSource, _ = ngsi.NewRemoteContextSource(registration)
+	contextSource, _ = newRemoteContextSource(registration)
 	contextRegistry.Register(contextSource)
 
-	router := createRequestRouter(contextRegistry, db, mq, logger)
+	router, err := createRequestRouter(contextRegistry, db, mq, alertConfig, readiness, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	port := os.Getenv("SNOWDEPTH_API_PORT")
 	if port == "" {
 		port = "8880"
 	}
 
-	logger.Info().Str("port", port).Msg("listening for incoming connections")
-
-	err := http.ListenAndServe(":"+port, router.impl)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("failed to start listening on port")
-	}
+	return &http.Server{
+		Addr:    ":" + port,
+		Handler: router.impl,
+	}, nil
 }
 
 type contextSource struct {
@@ -261,7 +325,7 @@ type contextSource struct {
 
 func convertDatabaseRecordToWeatherObserved(r *models.Snowdepth) *fiware.WeatherObserved {
 	if r != nil {
-		entity := fiware.NewWeatherObserved("snowHeight:"+r.Device, r.Latitude, r.Longitude, r.Timestamp)
+		entity := fiware.NewWeatherObserved("snowHeight:"+r.Device, r.Latitude, r.Longitude, r.Timestamp.UTC().Format(time.RFC3339))
 		entity.SnowHeight = types.NewNumberProperty(math.Round(float64(r.Depth*10)) / 10)
 		return entity
 	}
@@ -276,27 +340,100 @@ func (cs contextSource) CreateEntity(typeName, entityID string, req ngsi.Request
 func (cs contextSource) GetEntities(query ngsi.Query, callback ngsi.QueryEntitiesCallback) error {
 
 	var snowdepths []models.Snowdepth
+	var area *database.Area
 	var err error
 
-	if query.HasDeviceReference() {
+	ctx := requestContext(query.Request())
+
+	if polygon, ok := polygonFromRequest(query.Request()); ok {
+		area = &database.Area{Polygon: polygon}
+	} else if query.IsGeoQuery() {
+		area, err = newAreaFromGeoQuery(query.Geo())
+		if err != nil {
+			reportProblem(query.Request(), err)
+			return err
+		}
+	}
+
+	if query.IsTemporalQuery() {
+		from, to := query.Temporal().TimeSpan()
+		dbQuery := database.SnowdepthQuery{
+			From:  from,
+			To:    to,
+			Area:  area,
+			Limit: query.PaginationLimit(),
+		}
+
+		if query.HasDeviceReference() {
+			deviceID := strings.TrimPrefix(query.Device(), fiware.DeviceIDPrefix)
+			dbQuery.Device = &deviceID
+		}
+
+		snowdepths, err = cs.db.GetSnowdepthHistory(ctx, dbQuery)
+	} else if query.HasDeviceReference() {
 		deviceID := strings.TrimPrefix(query.Device(), fiware.DeviceIDPrefix)
-		snowdepths, err = cs.db.GetLatestSnowdepthsForDevice(deviceID)
+		snowdepths, err = cs.db.GetLatestSnowdepthsForDevice(ctx, deviceID)
 	} else {
-		snowdepths, err = cs.db.GetLatestSnowdepths()
+		snowdepths, err = cs.db.GetLatestSnowdepths(ctx)
 	}
 
-	if err == nil {
-		for _, v := range snowdepths {
-			err = callback(convertDatabaseRecordToWeatherObserved(&v))
-			if err != nil {
-				break
-			}
+	if err != nil {
+		reportProblem(query.Request(), err)
+		return err
+	}
+
+	if area != nil && !query.IsTemporalQuery() {
+		snowdepths = area.Filter(snowdepths)
+	}
+
+	for _, v := range snowdepths {
+		err = callback(convertDatabaseRecordToWeatherObserved(&v))
+		if err != nil {
+			break
 		}
 	}
 
 	return err
 }
 
+// requestContext returns the context of a request, so that datastore operations are
+// cancelled together with the request that triggered them
+func requestContext(r *http.Request) context.Context {
+	if r == nil {
+		return context.Background()
+	}
+	return r.Context()
+}
+
+// newAreaFromGeoQuery converts a near Point geo-query to an area. Note that GeoJSON coordinates
+// are ordered as longitude, latitude. Queries within a Polygon never get here, since they are
+// parsed by polygonMiddleware.
+func newAreaFromGeoQuery(geoQuery ngsi.GeoQuery) (*database.Area, error) {
+	if geoQuery.GeoRel != ngsi.GeoSpatialRelationNearPoint {
+		return nil, fmt.Errorf("%w: unsupported geo-spatial relationship %s", database.ErrValidation, geoQuery.GeoRel)
+	}
+
+	lon, lat, err := geoQuery.Point()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", database.ErrValidation, err.Error())
+	}
+
+	// The second value only tells if the distance is inclusive, and a missing distance is
+	// returned as zero
+	distance, _ := geoQuery.Distance()
+	if distance == 0 {
+		return nil, fmt.Errorf("%w: maxDistance is required for georel=near and must be a positive number of meters", database.ErrValidation)
+	}
+
+	area := &database.Area{Circle: &database.Circle{Latitude: lat, Longitude: lon, Radius: float64(distance)}}
+
+	if err := area.Validate(); err != nil {
+		return nil, err
+	}
+
+	return area, nil
+}
+
 func (cs contextSource) GetProvidedTypeFromID(entityID string) (string, error) {
 	return "", errors.New("not implemented")
 }
@@ -324,44 +461,66 @@ func (cs contextSource) RetrieveEntity(entityID string, req ngsi.Request) (ngsi.
 
 type ApiKey struct {
 	enabled bool
-	key     string
+	keys    auth.Keys
+	proxies trustedProxies
 }
 
-func newApiKeyMiddleware() *ApiKey {
+func newApiKeyMiddleware() (*ApiKey, error) {
+	keys, err := auth.LoadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		return nil, err
+	}
+
 	a := &ApiKey{
 		enabled: false,
-		key:     "",
+		keys:    keys,
+		proxies: proxies,
 	}
 
 	if b, err := strconv.ParseBool(os.Getenv("DIWISE_REQUIRE_API_KEY")); err == nil {
 		if b {
 
 			a.enabled = true
-			validKey := os.Getenv("DIWISE_API_KEY")
 
-			if len(validKey) != 0 {
-				a.key = validKey
-			} else {
-				panic("Api-Key is missing or invalid. Ensure that DIWISE_API_KEY is set to a valid value")
+			if keys.Empty() {
+				return nil, errors.New("api key is missing or invalid, ensure that DIWISE_API_KEY or SNOWDEPTH_API_KEYS is set to a valid value")
 			}
 		}
 	}
 
-	return a
+	return a, nil
 }
 
+// Handler identifies the client from its api key or bearer token and adds the identity to the
+// request context, together with the details that are recorded in the audit trail. When keys are
+// required, POST requests without a known key are rejected. Otherwise unknown credentials, such
+// as tokens added by a gateway, are ignored and the request is handled without an identity.
 func (a *ApiKey) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if a.enabled && strings.ToUpper(r.Method) == "POST" {
+		identity, found, err := a.keys.Authenticate(r)
 
-			apiKey := r.Header.Get("x-api-key")
+		if a.enabled && strings.ToUpper(r.Method) == "POST" && (err != nil || !found) {
+			ngsierrors.ReportUnauthorizedRequest(w, "Access denied. Invalid api-key found.")
+			return
+		}
 
-			if len(apiKey) == 0 || apiKey != a.key {
-				ngsierrors.ReportUnauthorizedRequest(w, "Access denied. Invalid api-key found.")
-				return
-			}
+		ctx := r.Context()
+		if found && err == nil {
+			ctx = auth.NewContext(ctx, identity)
 		}
-		next.ServeHTTP(w, r)
+
+		ctx = database.WithAuditSource(ctx, database.AuditSource{
+			Identity:  identity.Name,
+			ClientIP:  a.proxies.clientIP(r),
+			UserAgent: r.UserAgent(),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diwise/api-snowdepth/pkg/auth"
+)
+
+func TestApiKeyHandler(t *testing.T) {
+	t.Setenv("DIWISE_API_KEY", "")
+	t.Setenv("SNOWDEPTH_API_KEYS", "ops:k1:admin")
+
+	keys, err := auth.LoadKeys()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name             string
+		required         bool
+		method           string
+		headers          map[string]string
+		expectedStatus   int
+		expectedIdentity string
+	}{
+		{"known key", false, "POST", map[string]string{"x-api-key": "k1"}, http.StatusOK, "ops"},
+		{"no key", false, "POST", nil, http.StatusOK, ""},
+		{"unknown key is ignored", false, "POST", map[string]string{"x-api-key": "k2"}, http.StatusOK, ""},
+		{"gateway token is ignored", false, "POST", map[string]string{"Authorization": "Bearer eyJhbGciOi"}, http.StatusOK, ""},
+		{"required known key", true, "POST", map[string]string{"x-api-key": "k1"}, http.StatusOK, "ops"},
+		{"required known token", true, "POST", map[string]string{"Authorization": "Bearer k1"}, http.StatusOK, "ops"},
+		{"required no key", true, "POST", nil, http.StatusUnauthorized, ""},
+		{"required unknown key", true, "POST", map[string]string{"x-api-key": "k2"}, http.StatusUnauthorized, ""},
+		{"required gateway token", true, "POST", map[string]string{"Authorization": "Bearer eyJhbGciOi"}, http.StatusUnauthorized, ""},
+		{"required is only checked for POST", true, "GET", nil, http.StatusOK, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &ApiKey{enabled: tc.required, keys: keys}
+
+			identity := ""
+			handler := a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if id, ok := auth.FromContext(r.Context()); ok {
+					identity = id.Name
+				}
+			}))
+
+			r := httptest.NewRequest(tc.method, "/api/graphql", nil)
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+			if identity != tc.expectedIdentity {
+				t.Errorf("expected identity %q, got %q", tc.expectedIdentity, identity)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	t.Setenv("SNOWDEPTH_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1/32")
+
+	proxies, err := loadTrustedProxies()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		remote   string
+		headers  map[string]string
+		expected string
+	}{
+		{"direct", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted forwarded for", "203.0.113.7:5000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "203.0.113.7"},
+		{"untrusted real ip", "203.0.113.7:5000", map[string]string{"X-Real-IP": "198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy without headers", "10.1.2.3:5000", nil, "10.1.2.3"},
+		{"trusted forwarded for", "10.1.2.3:5000", map[string]string{"X-Forwarded-For": "198.51.100.1"}, "198.51.100.1"},
+		{"spoofed forwarded for", "10.1.2.3:5000", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1, 192.168.1.1"}, "198.51.100.1"},
+		{"invalid forwarded for", "10.1.2.3:5000", map[string]string{"X-Forwarded-For": "garbage, 10.0.0.2"}, "10.0.0.2"},
+		{"trusted real ip", "10.1.2.3:5000", map[string]string{"X-Real-IP": "198.51.100.1"}, "198.51.100.1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/graphql", nil)
+			r.RemoteAddr = tc.remote
+			for key, value := range tc.headers {
+				r.Header.Set(key, value)
+			}
+
+			if ip := proxies.clientIP(r); ip != tc.expected {
+				t.Errorf("expected client ip %s, got %s", tc.expected, ip)
+			}
+		})
+	}
+}
+
+func TestLoadTrustedProxiesRejectsInvalidNetworks(t *testing.T) {
+	t.Setenv("SNOWDEPTH_TRUSTED_PROXIES", "10.0.0.0/8,proxy")
+
+	if _, err := loadTrustedProxies(); err == nil {
+		t.Errorf("expected an error for an invalid network")
+	}
+}
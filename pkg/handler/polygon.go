@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	ngsi "github.com/diwise/ngsi-ld-golang/pkg/ngsi-ld"
+)
+
+var polygonCtxKey = &polygonContextKey{"polygon"}
+
+type polygonContextKey struct {
+	name string
+}
+
+// polygonQuery is a georel=within query with a Polygon, parsed by polygonMiddleware
+type polygonQuery struct {
+	polygon *database.Polygon
+	// rawQuery is the query of the request as it was received
+	rawQuery string
+}
+
+// polygonMiddleware parses geo-queries with georel=within and a Polygon itself, since the
+// ngsi-ld library only accepts polygons with five positions that describe a rectangle. The
+// parsed polygon, and the query as it was received, are kept in the context of the request,
+// and the geo-query parameters are removed from the request before it is handed to the
+// library. Invalid polygons are rejected with a bad request problem.
+func polygonMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+
+		if params.Get("georel") != ngsi.GeoSpatialRelationWithinRect {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if params.Get("geometry") != "Polygon" {
+			writeProblem(w, newProblemFromDatabaseError(
+				fmt.Errorf("%w: georel=within requires a Polygon geometry", database.ErrValidation),
+			))
+			return
+		}
+
+		polygon, err := parsePolygon(params.Get("coordinates"))
+		if err != nil {
+			writeProblem(w, newProblemFromDatabaseError(err))
+			return
+		}
+
+		query := polygonQuery{polygon: polygon, rawQuery: r.URL.RawQuery}
+
+		for _, param := range []string{"georel", "geometry", "coordinates"} {
+			params.Del(param)
+		}
+
+		r = r.Clone(context.WithValue(r.Context(), polygonCtxKey, query))
+		r.URL.RawQuery = params.Encode()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parsePolygon parses the coordinates of a GeoJSON polygon. Only the outer ring is supported,
+// and the coordinates of each position are ordered as longitude, latitude.
+func parsePolygon(coordinates string) (*database.Polygon, error) {
+	var rings [][][]float64
+
+	if err := json.Unmarshal([]byte(coordinates), &rings); err != nil {
+		return nil, fmt.Errorf("%w: the coordinates of a Polygon must be a list of rings of positions", database.ErrValidation)
+	}
+
+	if len(rings) != 1 {
+		return nil, fmt.Errorf("%w: a Polygon must have exactly one ring, holes are not supported", database.ErrValidation)
+	}
+
+	polygon := &database.Polygon{}
+
+	for _, position := range rings[0] {
+		if len(position) != 2 {
+			return nil, fmt.Errorf("%w: each position of a Polygon must be a longitude and a latitude", database.ErrValidation)
+		}
+		polygon.Ring = append(polygon.Ring, database.Position{Latitude: position[1], Longitude: position[0]})
+	}
+
+	if err := (database.Area{Polygon: polygon}).Validate(); err != nil {
+		return nil, err
+	}
+
+	return polygon, nil
+}
+
+// polygonFromRequest returns the polygon that polygonMiddleware has parsed from the request
+func polygonFromRequest(r *http.Request) (*database.Polygon, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	query, ok := r.Context().Value(polygonCtxKey).(polygonQuery)
+	return query.polygon, ok
+}
+
+// receivedRawQuery returns the query of a request as it was received, before polygonMiddleware
+// removed the geo-query parameters from it
+func receivedRawQuery(r *http.Request) string {
+	if query, ok := r.Context().Value(polygonCtxKey).(polygonQuery); ok {
+		return query.rawQuery
+	}
+	return r.URL.RawQuery
+}
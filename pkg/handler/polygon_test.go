@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+func TestPolygonMiddleware(t *testing.T) {
+	triangle := `[[[17.2,62.3],[17.4,62.3],[17.3,62.5],[17.2,62.3]]]`
+
+	tests := []struct {
+		name            string
+		params          url.Values
+		expectedStatus  int
+		expectedPolygon []database.Position
+		expectedQuery   url.Values
+	}{
+		{
+			name:            "polygon",
+			params:          url.Values{"type": {"WeatherObserved"}, "georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {triangle}},
+			expectedStatus:  http.StatusOK,
+			expectedPolygon: []database.Position{{Latitude: 62.3, Longitude: 17.2}, {Latitude: 62.3, Longitude: 17.4}, {Latitude: 62.5, Longitude: 17.3}, {Latitude: 62.3, Longitude: 17.2}},
+			expectedQuery:   url.Values{"type": {"WeatherObserved"}},
+		},
+		{
+			name:            "polygon with negative coordinates",
+			params:          url.Values{"type": {"WeatherObserved"}, "georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {`[[[-1,-1],[1,-1],[0,1]]]`}},
+			expectedStatus:  http.StatusOK,
+			expectedPolygon: []database.Position{{Latitude: -1, Longitude: -1}, {Latitude: -1, Longitude: 1}, {Latitude: 1, Longitude: 0}},
+			expectedQuery:   url.Values{"type": {"WeatherObserved"}},
+		},
+		{
+			name:           "near is passed on",
+			params:         url.Values{"type": {"WeatherObserved"}, "georel": {"near;maxDistance==100"}, "geometry": {"Point"}, "coordinates": {"[17.3,62.4]"}},
+			expectedStatus: http.StatusOK,
+			expectedQuery:  url.Values{"type": {"WeatherObserved"}, "georel": {"near;maxDistance==100"}, "geometry": {"Point"}, "coordinates": {"[17.3,62.4]"}},
+		},
+		{
+			name:           "within requires a polygon",
+			params:         url.Values{"georel": {"within"}, "geometry": {"Point"}, "coordinates": {"[17.3,62.4]"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "holes are not supported",
+			params:         url.Values{"georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {`[[[0,0],[1,0],[0,1]],[[0.1,0.1],[0.2,0.1],[0.1,0.2]]]`}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too few corners",
+			params:         url.Values{"georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {`[[[0,0],[1,0],[0,0]]]`}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid coordinates",
+			params:         url.Values{"georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {`[[[0,0],[1,0],[0,91]]]`}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not json",
+			params:         url.Values{"georel": {"within"}, "geometry": {"Polygon"}, "coordinates": {`[[0,0],[1,0]`}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			incoming := httptest.NewRequest("GET", "/ngsi-ld/v1/entities?"+tc.params.Encode(), nil)
+
+			var received *http.Request
+			handler := polygonMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, incoming)
+
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, w.Code)
+			}
+
+			if tc.expectedStatus != http.StatusOK {
+				if received != nil {
+					t.Errorf("expected the request to be rejected")
+				}
+				return
+			}
+
+			if query := received.URL.Query(); !reflect.DeepEqual(query, tc.expectedQuery) {
+				t.Errorf("expected the query %v to be passed on, got %v", tc.expectedQuery, query)
+			}
+
+			if receivedRawQuery(received) != incoming.URL.RawQuery {
+				t.Errorf("expected the received query to be kept, got %s", receivedRawQuery(received))
+			}
+
+			polygon, ok := polygonFromRequest(received)
+			if ok != (tc.expectedPolygon != nil) {
+				t.Fatalf("expected a polygon to be parsed to be %t", tc.expectedPolygon != nil)
+			}
+			if ok && !reflect.DeepEqual(polygon.Ring, tc.expectedPolygon) {
+				t.Errorf("expected polygon %v, got %v", tc.expectedPolygon, polygon.Ring)
+			}
+		})
+	}
+}
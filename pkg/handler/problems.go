@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	ngsierrors "github.com/diwise/ngsi-ld-golang/pkg/ngsi-ld/errors"
+)
+
+// problem is an NGSI-LD problem detail (RFC 7807) together with the status code it should
+// be returned with
+type problem struct {
+	status int
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func newProblemFromDatabaseError(err error) *problem {
+	switch {
+	case errors.Is(err, database.ErrValidation):
+		return &problem{http.StatusBadRequest, "https://uri.etsi.org/ngsi-ld/errors/BadRequestData", "Bad Request Data", err.Error()}
+	case errors.Is(err, database.ErrDuplicateMeasurement), errors.Is(err, database.ErrConflictingMeasurement):
+		return &problem{http.StatusConflict, "https://uri.etsi.org/ngsi-ld/errors/AlreadyExists", "Already Exists", err.Error()}
+	case errors.Is(err, database.ErrNotFound):
+		return &problem{http.StatusNotFound, "https://uri.etsi.org/ngsi-ld/errors/ResourceNotFound", "Resource Not Found", err.Error()}
+	case errors.Is(err, database.ErrUnavailable):
+		return &problem{http.StatusServiceUnavailable, "https://uri.etsi.org/ngsi-ld/errors/InternalError", "Service Unavailable", err.Error()}
+	}
+
+	return nil
+}
+
+var problemCtxKey = &problemContextKey{"problem"}
+
+type problemContextKey struct {
+	name string
+}
+
+// reportProblem records a classified database error for the current request, so that
+// it is returned to the client instead of the generic internal error that the ngsi-ld
+// handlers report for all context source errors
+func reportProblem(r *http.Request, err error) {
+	if r == nil {
+		return
+	}
+
+	pw, ok := r.Context().Value(problemCtxKey).(*problemResponseWriter)
+	if ok {
+		pw.problem = newProblemFromDatabaseError(err)
+	}
+}
+
+// problemMiddleware makes it possible for context sources to report problems using reportProblem
+func problemMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pw := &problemResponseWriter{ResponseWriter: w}
+		ctx := context.WithValue(r.Context(), problemCtxKey, pw)
+		next.ServeHTTP(pw, r.WithContext(ctx))
+	})
+}
+
+type problemResponseWriter struct {
+	http.ResponseWriter
+	problem  *problem
+	replaced bool
+}
+
+func (pw *problemResponseWriter) WriteHeader(statusCode int) {
+	if pw.problem == nil || statusCode < http.StatusBadRequest {
+		pw.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	pw.replaced = true
+
+	writeProblem(pw.ResponseWriter, pw.problem)
+}
+
+// writeProblem writes a problem report as the response
+func writeProblem(w http.ResponseWriter, p *problem) {
+	body, _ := json.MarshalIndent(p, "", "  ")
+
+	w.Header().Set("Content-Type", ngsierrors.ProblemReportContentType)
+	w.WriteHeader(p.status)
+	w.Write(body)
+}
+
+func (pw *problemResponseWriter) Write(b []byte) (int, error) {
+	if pw.replaced {
+		// Discard the original problem report
+		return len(b), nil
+	}
+
+	return pw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// trustedProxies are the networks of the proxies that are trusted to report the address of
+// the client in the X-Forwarded-For and X-Real-IP headers
+type trustedProxies []*net.IPNet
+
+// loadTrustedProxies reads a comma separated list of CIDRs from SNOWDEPTH_TRUSTED_PROXIES
+func loadTrustedProxies() (trustedProxies, error) {
+	proxies := trustedProxies{}
+
+	for _, value := range strings.Split(os.Getenv("SNOWDEPTH_TRUSTED_PROXIES"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q in SNOWDEPTH_TRUSTED_PROXIES: %w", value, err)
+		}
+
+		proxies = append(proxies, network)
+	}
+
+	return proxies, nil
+}
+
+func (p trustedProxies) contains(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client. The forwarding headers are only used when the
+// request comes from a trusted proxy, in which case the client is the last address in
+// X-Forwarded-For that is not a trusted proxy, or the address in X-Real-IP.
+func (p trustedProxies) clientIP(r *http.Request) string {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(client)
+	if ip == nil || !p.contains(ip) {
+		return client
+	}
+
+	if header := r.Header.Get("X-Forwarded-For"); header != "" {
+		addrs := strings.Split(header, ",")
+
+		for idx := len(addrs) - 1; idx >= 0; idx-- {
+			forwarded := net.ParseIP(strings.TrimSpace(addrs[idx]))
+			if forwarded == nil {
+				break
+			}
+
+			client = forwarded.String()
+			if !p.contains(forwarded) {
+				break
+			}
+		}
+
+		return client
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+
+	return client
+}
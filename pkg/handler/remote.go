@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/diwise/api-snowdepth/pkg/tracing"
+	ngsi "github.com/diwise/ngsi-ld-golang/pkg/ngsi-ld"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedContextSource creates a client span for every request that is forwarded to a remote
+// context source. The remote source forwards the request that it is given, and rewrites its
+// url and headers on the way, so it is given a copy of the incoming request instead, with the
+// query as it was received. The trace context of the span is injected into the headers of the
+// copy, and the trace continues in the remote source.
+type tracedContextSource struct {
+	ngsi.ContextSource
+	endpoint string
+}
+
+// newRemoteContextSource creates a traced remote context source for a registration
+func newRemoteContextSource(registration ngsi.CsourceRegistration) (ngsi.ContextSource, error) {
+	source, err := ngsi.NewRemoteContextSource(registration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tracedContextSource{ContextSource: source, endpoint: registration.Endpoint()}, nil
+}
+
+// start starts a span for a request to the remote source and returns a copy of the request
+// that propagates the span, for the remote source to forward. The returned function ends
+// the span.
+func (s *tracedContextSource) start(operation string, r *http.Request) (*http.Request, func(error)) {
+	if r == nil {
+		return nil, func(error) {}
+	}
+
+	ctx, span := tracing.Start(r.Context(), "contextSource "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ngsi.context_source", s.endpoint)),
+	)
+
+	outgoing := r.Clone(ctx)
+	outgoing.URL.RawQuery = receivedRawQuery(r)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(outgoing.Header))
+
+	return outgoing, func(err error) { tracing.End(span, err) }
+}
+
+func (s *tracedContextSource) CreateEntity(typeName, entityID string, req ngsi.Request) error {
+	outgoing, end := s.start("CreateEntity", req.Request())
+	err := s.ContextSource.CreateEntity(typeName, entityID, outgoingRequest{req, outgoing})
+	end(err)
+	return err
+}
+
+func (s *tracedContextSource) GetEntities(query ngsi.Query, callback ngsi.QueryEntitiesCallback) error {
+	outgoing, end := s.start("GetEntities", query.Request())
+	err := s.ContextSource.GetEntities(outgoingQuery{query, outgoing}, callback)
+	end(err)
+	return err
+}
+
+func (s *tracedContextSource) RetrieveEntity(entityID string, req ngsi.Request) (ngsi.Entity, error) {
+	outgoing, end := s.start("RetrieveEntity", req.Request())
+	entity, err := s.ContextSource.RetrieveEntity(entityID, outgoingRequest{req, outgoing})
+	end(err)
+	return entity, err
+}
+
+func (s *tracedContextSource) UpdateEntityAttributes(entityID string, req ngsi.Request) error {
+	outgoing, end := s.start("UpdateEntityAttributes", req.Request())
+	err := s.ContextSource.UpdateEntityAttributes(entityID, outgoingRequest{req, outgoing})
+	end(err)
+	return err
+}
+
+// outgoingRequest is an ngsi request that is forwarded as a copy of the incoming request
+type outgoingRequest struct {
+	incoming ngsi.Request
+	outgoing *http.Request
+}
+
+func (r outgoingRequest) BodyReader() io.Reader {
+	return r.incoming.BodyReader()
+}
+
+func (r outgoingRequest) DecodeBodyInto(v interface{}) error {
+	return r.incoming.DecodeBodyInto(v)
+}
+
+func (r outgoingRequest) Request() *http.Request {
+	return r.outgoing
+}
+
+// outgoingQuery is a query that is forwarded as a copy of the incoming request
+type outgoingQuery struct {
+	ngsi.Query
+	outgoing *http.Request
+}
+
+func (q outgoingQuery) Request() *http.Request {
+	return q.outgoing
+}
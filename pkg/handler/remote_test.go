@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ngsi "github.com/diwise/ngsi-ld-golang/pkg/ngsi-ld"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// forwardingSource records the requests that it is asked to forward, and rewrites them like
+// the remote context sources of the ngsi-ld library do
+type forwardingSource struct {
+	ngsi.ContextSource
+	forwarded []*http.Request
+}
+
+func (s *forwardingSource) forward(r *http.Request) {
+	r.URL.Host = "remote.example.com"
+	r.Header.Add("User-Agent", "ngsi-context-broker/0.1")
+	s.forwarded = append(s.forwarded, r)
+}
+
+func (s *forwardingSource) GetEntities(query ngsi.Query, callback ngsi.QueryEntitiesCallback) error {
+	s.forward(query.Request())
+	return nil
+}
+
+func (s *forwardingSource) RetrieveEntity(entityID string, req ngsi.Request) (ngsi.Entity, error) {
+	s.forward(req.Request())
+	return nil, nil
+}
+
+type requestQuery struct {
+	ngsi.Query
+	r *http.Request
+}
+
+func (q requestQuery) Request() *http.Request {
+	return q.r
+}
+
+type requestOnly struct {
+	r *http.Request
+}
+
+func (r requestOnly) BodyReader() io.Reader {
+	return r.r.Body
+}
+
+func (r requestOnly) DecodeBodyInto(v interface{}) error {
+	return json.NewDecoder(r.r.Body).Decode(v)
+}
+
+func (r requestOnly) Request() *http.Request {
+	return r.r
+}
+
+func TestTracedContextSourceForwardsACopy(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled, Remote: true,
+	}))
+
+	tests := []struct {
+		name string
+		call func(*tracedContextSource, *http.Request) error
+	}{
+		{"GetEntities", func(s *tracedContextSource, r *http.Request) error {
+			return s.GetEntities(requestQuery{r: r}, func(ngsi.Entity) error { return nil })
+		}},
+		{"RetrieveEntity", func(s *tracedContextSource, r *http.Request) error {
+			_, err := s.RetrieveEntity("urn:ngsi-ld:Beach:1", requestOnly{r: r})
+			return err
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			remote := &forwardingSource{}
+			source := &tracedContextSource{ContextSource: remote, endpoint: "http://remote.example.com"}
+
+			incoming := httptest.NewRequest("GET", "/ngsi-ld/v1/entities?type=Beach", nil).WithContext(ctx)
+
+			if err := tc.call(source, incoming); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(remote.forwarded) != 1 || remote.forwarded[0] == incoming {
+				t.Fatalf("expected a copy of the incoming request to be forwarded")
+			}
+
+			if traceparent := remote.forwarded[0].Header.Get("traceparent"); !strings.Contains(traceparent, traceID.String()) {
+				t.Errorf("expected the forwarded request to continue the trace, got traceparent %q", traceparent)
+			}
+
+			if incoming.Header.Get("traceparent") != "" || incoming.Header.Get("User-Agent") != "" || incoming.URL.Host != "" {
+				t.Errorf("expected the incoming request to be left alone, got %s with headers %v", incoming.URL, incoming.Header)
+			}
+		})
+	}
+}
+
+func TestTracedContextSourceForwardsTheReceivedQuery(t *testing.T) {
+	remote := &forwardingSource{}
+	source := &tracedContextSource{ContextSource: remote, endpoint: "http://remote.example.com"}
+
+	incoming := httptest.NewRequest("GET", "/ngsi-ld/v1/entities?type=Beach&georel=within&geometry=Polygon&coordinates=%5B%5B%5B-1%2C-1%5D%2C%5B1%2C-1%5D%2C%5B0%2C1%5D%5D%5D", nil)
+
+	var parsed *http.Request
+	polygonMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsed = r
+	})).ServeHTTP(httptest.NewRecorder(), incoming)
+
+	if parsed == nil {
+		t.Fatalf("expected the polygon to be accepted")
+	}
+
+	if err := source.GetEntities(requestQuery{r: parsed}, func(ngsi.Entity) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(remote.forwarded) != 1 || remote.forwarded[0].URL.RawQuery != incoming.URL.RawQuery {
+		t.Errorf("expected the query %q to be forwarded, got %+v", incoming.URL.RawQuery, remote.forwarded)
+	}
+}
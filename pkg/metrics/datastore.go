@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// instrumentedDatastore records the duration of every Datastore call, and counts the
+// measurements that are ingested through it. It does not embed the Datastore, so that new
+// methods can not be added to the interface without being instrumented.
+type instrumentedDatastore struct {
+	db database.Datastore
+}
+
+// NewInstrumentedDatastore wraps a Datastore so that its calls are measured
+func NewInstrumentedDatastore(db database.Datastore) database.Datastore {
+	return &instrumentedDatastore{db: db}
+}
+
+func (d *instrumentedDatastore) AddManualSnowdepthMeasurement(ctx context.Context, latitude, longitude, depth float64) (*models.Snowdepth, error) {
+	defer observeDatastore("AddManualSnowdepthMeasurement", time.Now())
+
+	measurement, err := d.db.AddManualSnowdepthMeasurement(ctx, latitude, longitude, depth)
+	recordIngest(measurement, database.IngestInserted, err, time.Now())
+
+	return measurement, err
+}
+
+func (d *instrumentedDatastore) AddSnowdepthMeasurement(ctx context.Context, device *string, latitude, longitude, depth float64, when string) (*models.Snowdepth, database.IngestOutcome, error) {
+	defer observeDatastore("AddSnowdepthMeasurement", time.Now())
+
+	measurement, outcome, err := d.db.AddSnowdepthMeasurement(ctx, device, latitude, longitude, depth, when)
+	recordIngest(measurement, outcome, err, time.Now())
+
+	return measurement, outcome, err
+}
+
+func (d *instrumentedDatastore) AddSnowdepthMeasurements(ctx context.Context, batch []database.NewMeasurement) ([]database.IngestResult, error) {
+	defer observeDatastore("AddSnowdepthMeasurements", time.Now())
+
+	results, err := d.db.AddSnowdepthMeasurements(ctx, batch)
+	now := time.Now()
+
+	if err != nil {
+		ingestFailures.WithLabelValues(failureReason(database.IngestInserted, err)).Add(float64(len(batch)))
+		return results, err
+	}
+
+	for _, result := range results {
+		recordIngest(result.Measurement, result.Outcome, result.Err, now)
+	}
+
+	return results, nil
+}
+
+func (d *instrumentedDatastore) CorrectSnowdepthMeasurement(ctx context.Context, id uint, correction database.SnowdepthCorrection) (*models.Snowdepth, error) {
+	defer observeDatastore("CorrectSnowdepthMeasurement", time.Now())
+	return d.db.CorrectSnowdepthMeasurement(ctx, id, correction)
+}
+
+func (d *instrumentedDatastore) RetractSnowdepthMeasurement(ctx context.Context, id uint, change database.MeasurementChange) (*models.Snowdepth, error) {
+	defer observeDatastore("RetractSnowdepthMeasurement", time.Now())
+	return d.db.RetractSnowdepthMeasurement(ctx, id, change)
+}
+
+func (d *instrumentedDatastore) GetLatestSnowdepths(ctx context.Context) ([]models.Snowdepth, error) {
+	defer observeDatastore("GetLatestSnowdepths", time.Now())
+	return d.db.GetLatestSnowdepths(ctx)
+}
+
+func (d *instrumentedDatastore) GetLatestSnowdepthsForDevice(ctx context.Context, device string) ([]models.Snowdepth, error) {
+	defer observeDatastore("GetLatestSnowdepthsForDevice", time.Now())
+	return d.db.GetLatestSnowdepthsForDevice(ctx, device)
+}
+
+func (d *instrumentedDatastore) GetSnowdepthHistory(ctx context.Context, query database.SnowdepthQuery) ([]models.Snowdepth, error) {
+	defer observeDatastore("GetSnowdepthHistory", time.Now())
+	return d.db.GetSnowdepthHistory(ctx, query)
+}
+
+func (d *instrumentedDatastore) GetSnowdepthStatistics(ctx context.Context, device *string, from, to time.Time, interval database.AggregationInterval) ([]models.SnowdepthStatistics, error) {
+	defer observeDatastore("GetSnowdepthStatistics", time.Now())
+	return d.db.GetSnowdepthStatistics(ctx, device, from, to, interval)
+}
+
+func (d *instrumentedDatastore) GetAuditTrail(ctx context.Context, query database.AuditQuery) ([]models.SnowdepthAuditEntry, error) {
+	defer observeDatastore("GetAuditTrail", time.Now())
+	return d.db.GetAuditTrail(ctx, query)
+}
+
+func (d *instrumentedDatastore) GetQuarantinedSnowdepths(ctx context.Context, query database.QuarantineQuery) ([]models.QuarantinedSnowdepth, error) {
+	defer observeDatastore("GetQuarantinedSnowdepths", time.Now())
+	return d.db.GetQuarantinedSnowdepths(ctx, query)
+}
+
+func (d *instrumentedDatastore) GetDeviceCalibrations(ctx context.Context, device string) ([]models.DeviceCalibration, error) {
+	defer observeDatastore("GetDeviceCalibrations", time.Now())
+	return d.db.GetDeviceCalibrations(ctx, device)
+}
+
+func (d *instrumentedDatastore) AddDeviceCalibration(ctx context.Context, calibration database.Calibration, recompute bool) (database.CalibrationChange, error) {
+	defer observeDatastore("AddDeviceCalibration", time.Now())
+	return d.db.AddDeviceCalibration(ctx, calibration, recompute)
+}
+
+func (d *instrumentedDatastore) UpdateDeviceCalibration(ctx context.Context, id uint, calibration database.Calibration, recompute bool) (database.CalibrationChange, error) {
+	defer observeDatastore("UpdateDeviceCalibration", time.Now())
+	return d.db.UpdateDeviceCalibration(ctx, id, calibration, recompute)
+}
+
+func (d *instrumentedDatastore) DeleteDeviceCalibration(ctx context.Context, id uint, recompute bool) (database.CalibrationChange, error) {
+	defer observeDatastore("DeleteDeviceCalibration", time.Now())
+	return d.db.DeleteDeviceCalibration(ctx, id, recompute)
+}
+
+func (d *instrumentedDatastore) GetDeviceHealth(ctx context.Context, device string) (*models.DeviceHealth, error) {
+	defer observeDatastore("GetDeviceHealth", time.Now())
+	return d.db.GetDeviceHealth(ctx, device)
+}
+
+func (d *instrumentedDatastore) GetDevicesHealth(ctx context.Context, status *string) ([]models.DeviceHealth, error) {
+	defer observeDatastore("GetDevicesHealth", time.Now())
+	return d.db.GetDevicesHealth(ctx, status)
+}
+
+func (d *instrumentedDatastore) SetDeviceExpectedInterval(ctx context.Context, device string, interval *time.Duration) (*models.DeviceHealth, error) {
+	defer observeDatastore("SetDeviceExpectedInterval", time.Now())
+	return d.db.SetDeviceExpectedInterval(ctx, device, interval)
+}
+
+func (d *instrumentedDatastore) UpdateDeviceStatuses(ctx context.Context, policy database.HealthPolicy) (database.HealthResult, error) {
+	defer observeDatastore("UpdateDeviceStatuses", time.Now())
+	return d.db.UpdateDeviceStatuses(ctx, policy)
+}
+
+func (d *instrumentedDatastore) GetAlertRules(ctx context.Context) ([]models.AlertRule, error) {
+	defer observeDatastore("GetAlertRules", time.Now())
+	return d.db.GetAlertRules(ctx)
+}
+
+func (d *instrumentedDatastore) AddAlertRule(ctx context.Context, rule database.AlertRuleInput) (*models.AlertRule, error) {
+	defer observeDatastore("AddAlertRule", time.Now())
+	return d.db.AddAlertRule(ctx, rule)
+}
+
+func (d *instrumentedDatastore) UpdateAlertRule(ctx context.Context, id uint, rule database.AlertRuleInput) (*models.AlertRule, error) {
+	defer observeDatastore("UpdateAlertRule", time.Now())
+	return d.db.UpdateAlertRule(ctx, id, rule)
+}
+
+func (d *instrumentedDatastore) DeleteAlertRule(ctx context.Context, id uint) (*models.AlertRule, error) {
+	defer observeDatastore("DeleteAlertRule", time.Now())
+	return d.db.DeleteAlertRule(ctx, id)
+}
+
+func (d *instrumentedDatastore) RelayOutbox(ctx context.Context, limit int, publish func(context.Context, database.OutboxMessage) error) (database.OutboxResult, error) {
+	defer observeDatastore("RelayOutbox", time.Now())
+	return d.db.RelayOutbox(ctx, limit, publish)
+}
+
+func (d *instrumentedDatastore) AddDeadLetter(ctx context.Context, letter database.NewDeadLetter) (*models.DeadLetter, error) {
+	defer observeDatastore("AddDeadLetter", time.Now())
+	return d.db.AddDeadLetter(ctx, letter)
+}
+
+func (d *instrumentedDatastore) GetDeadLetters(ctx context.Context, query database.DeadLetterQuery) ([]models.DeadLetter, error) {
+	defer observeDatastore("GetDeadLetters", time.Now())
+	return d.db.GetDeadLetters(ctx, query)
+}
+
+func (d *instrumentedDatastore) GetDeadLetter(ctx context.Context, id uint64) (*models.DeadLetter, error) {
+	defer observeDatastore("GetDeadLetter", time.Now())
+	return d.db.GetDeadLetter(ctx, id)
+}
+
+func (d *instrumentedDatastore) RecordDeadLetterReplay(ctx context.Context, id uint64, replayErr error) (*models.DeadLetter, error) {
+	defer observeDatastore("RecordDeadLetterReplay", time.Now())
+	return d.db.RecordDeadLetterReplay(ctx, id, replayErr)
+}
+
+func (d *instrumentedDatastore) ApplyRetention(ctx context.Context, policy database.RetentionPolicy) (database.RetentionResult, error) {
+	defer observeDatastore("ApplyRetention", time.Now())
+	return d.db.ApplyRetention(ctx, policy)
+}
+
+func (d *instrumentedDatastore) Ping(ctx context.Context) error {
+	defer observeDatastore("Ping", time.Now())
+	return d.db.Ping(ctx)
+}
+
+func (d *instrumentedDatastore) Close() error {
+	return d.db.Close()
+}
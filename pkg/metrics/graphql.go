@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// GraphQLExtension records the duration of every GraphQL operation. Operations without a
+// name are recorded as anonymous, so clients should name their operations.
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = GraphQLExtension{}
+
+// ExtensionName returns the name of the extension
+func (GraphQLExtension) ExtensionName() string {
+	return "Metrics"
+}
+
+// Validate accepts any schema
+func (GraphQLExtension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse observes the time from when the operation was received until its
+// response was produced
+func (GraphQLExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+
+	operation, kind, result := "anonymous", "unknown", "success"
+
+	if oc.OperationName != "" {
+		operation = oc.OperationName
+	}
+
+	if oc.Operation != nil {
+		kind = string(oc.Operation.Operation)
+	}
+
+	if resp == nil || len(resp.Errors) > 0 {
+		result = "error"
+	}
+
+	graphqlDuration.WithLabelValues(operation, kind, result).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+
+	return resp
+}
@@ -0,0 +1,180 @@
+// Package metrics collects the Prometheus metrics of the service and serves them on /metrics
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// Sources of ingested measurements
+const (
+	SourceManual = "manual"
+	SourceSensor = "sensor"
+)
+
+// Reasons why a measurement could not be ingested
+const (
+	FailureMalformed   = "malformed"
+	FailureInvalid     = "invalid"
+	FailureQuarantined = "quarantined"
+	FailureConflict    = "conflict"
+	FailureUnavailable = "unavailable"
+	FailureError       = "error"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	measurementsIngested = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowdepth",
+		Name:      "measurements_ingested_total",
+		Help:      "Number of measurements that have been stored, as new measurements or by overwriting duplicates.",
+	}, []string{"device", "source"})
+
+	ingestFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowdepth",
+		Name:      "ingest_failures_total",
+		Help:      "Number of attempts to store a measurement that failed, by reason.",
+	}, []string{"reason"})
+
+	ingestLag = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "snowdepth",
+		Name:      "ingest_lag_seconds",
+		Help:      "Time from when a sensor measured the depth until the measurement was stored.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+	})
+
+	deadLetters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowdepth",
+		Name:      "dead_letters_total",
+		Help:      "Number of received messages that were stored as dead letters, by reason.",
+	}, []string{"reason"})
+
+	datastoreDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "snowdepth",
+		Name:      "datastore_duration_seconds",
+		Help:      "Duration of Datastore calls, by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	graphqlDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "snowdepth",
+		Name:      "graphql_operation_duration_seconds",
+		Help:      "Duration of GraphQL operations, by operation name and type, and whether they returned errors.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "type", "result"})
+
+	ngsiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "snowdepth",
+		Name:      "ngsi_requests_total",
+		Help:      "Number of NGSI-LD requests, by handler and status code.",
+	}, []string{"handler", "code"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		measurementsIngested,
+		ingestFailures,
+		ingestLag,
+		deadLetters,
+		datastoreDuration,
+		graphqlDuration,
+		ngsiRequests,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RecordIngestFailure counts a measurement that could not be stored
+func RecordIngestFailure(reason string) {
+	ingestFailures.WithLabelValues(reason).Inc()
+}
+
+// RecordDeadLetter counts a message that was stored as a dead letter
+func RecordDeadLetter(reason string) {
+	deadLetters.WithLabelValues(reason).Inc()
+}
+
+// recordIngest counts the result of storing a measurement. Only measurements that were
+// stored count as ingested, and the lag is only known for measurements from sensors.
+func recordIngest(m *models.Snowdepth, outcome database.IngestOutcome, err error, now time.Time) {
+	if err != nil {
+		RecordIngestFailure(failureReason(outcome, err))
+		return
+	}
+
+	if outcome != database.IngestInserted && outcome != database.IngestOverwritten {
+		return
+	}
+
+	if m.Device == "" {
+		measurementsIngested.WithLabelValues("", SourceManual).Inc()
+		return
+	}
+
+	measurementsIngested.WithLabelValues(m.Device, SourceSensor).Inc()
+	ingestLag.Observe(now.Sub(m.Timestamp).Seconds())
+}
+
+// failureReason returns the reason why a measurement could not be stored
+func failureReason(outcome database.IngestOutcome, err error) string {
+	switch {
+	case outcome == database.IngestQuarantined:
+		return FailureQuarantined
+	case errors.Is(err, database.ErrConflictingMeasurement):
+		return FailureConflict
+	case errors.Is(err, database.ErrValidation):
+		return FailureInvalid
+	case database.IsRetryable(err):
+		return FailureUnavailable
+	}
+	return FailureError
+}
+
+// observeDatastore records the duration of a Datastore call that started at start
+func observeDatastore(method string, start time.Time) {
+	datastoreDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// NGSIHandler counts the requests that are served by an NGSI-LD handler
+func NGSIHandler(name string, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		ngsiRequests.WithLabelValues(name, strconv.Itoa(sw.status)).Inc()
+	}
+}
+
+// statusWriter remembers the status code of a response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wrote {
+		w.status = status
+		w.wrote = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rs/zerolog"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcome  database.IngestOutcome
+		err      error
+		expected string
+	}{
+		{"quarantined", database.IngestQuarantined, fmt.Errorf("%w: rejected", database.ErrValidation), FailureQuarantined},
+		{"conflict", database.IngestInserted, fmt.Errorf("%w: different depth", database.ErrConflictingMeasurement), FailureConflict},
+		{"invalid", database.IngestInserted, fmt.Errorf("%w: bad timestamp", database.ErrValidation), FailureInvalid},
+		{"unavailable", database.IngestInserted, fmt.Errorf("%w: connection refused", database.ErrUnavailable), FailureUnavailable},
+		{"other", database.IngestInserted, errors.New("unexpected"), FailureError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if reason := failureReason(tc.outcome, tc.err); reason != tc.expected {
+				t.Errorf("expected reason %s, got %s", tc.expected, reason)
+			}
+		})
+	}
+}
+
+func TestInstrumentedDatastoreCountsIngestedMeasurements(t *testing.T) {
+	ctx := context.Background()
+	rules := database.ValidationRules{MinDepth: 0, MaxDepth: 300, MaxFutureSkew: 5 * time.Minute}
+	db := NewInstrumentedDatastore(database.NewInMemoryDatastore(zerolog.Nop(), database.DuplicateIgnore, rules))
+
+	device := "metrics-test"
+	ingested := measurementsIngested.WithLabelValues(device, SourceSensor)
+	quarantined := ingestFailures.WithLabelValues(FailureQuarantined)
+
+	before := []float64{testutil.ToFloat64(ingested), testutil.ToFloat64(quarantined)}
+	callsBefore := observations(t, "AddSnowdepthMeasurement")
+
+	when := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	if _, _, err := db.AddSnowdepthMeasurement(ctx, &device, 62.39, 17.30, 25, when); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// A duplicate is ignored and does not count as ingested
+	if _, _, err := db.AddSnowdepthMeasurement(ctx, &device, 62.39, 17.30, 25, when); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err := db.AddSnowdepthMeasurement(ctx, &device, 62.39, 17.30, 301, time.Now().UTC().Format(time.RFC3339)); err == nil {
+		t.Fatalf("expected the measurement to be quarantined")
+	}
+
+	if delta := testutil.ToFloat64(ingested) - before[0]; delta != 1 {
+		t.Errorf("expected 1 measurement to be counted as ingested, got %v", delta)
+	}
+	if delta := testutil.ToFloat64(quarantined) - before[1]; delta != 1 {
+		t.Errorf("expected 1 measurement to be counted as quarantined, got %v", delta)
+	}
+	if delta := observations(t, "AddSnowdepthMeasurement") - callsBefore; delta != 3 {
+		t.Errorf("expected the duration of 3 calls to be recorded, got %d", delta)
+	}
+}
+
+// observations returns the number of calls to a Datastore method that have been recorded
+func observations(t *testing.T, method string) uint64 {
+	t.Helper()
+
+	metric := &dto.Metric{}
+	if err := datastoreDuration.WithLabelValues(method).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("failed to read histogram: %s", err)
+	}
+
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestNGSIHandlerCountsRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected string
+	}{
+		{"implicit ok", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("{}")) }, "200"},
+		{"not found", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }, "404"},
+		{"status after body", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("{}"))
+			w.WriteHeader(http.StatusInternalServerError)
+		}, "200"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := ngsiRequests.WithLabelValues(tc.name, tc.expected)
+			before := testutil.ToFloat64(counter)
+
+			NGSIHandler(tc.name, tc.handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ngsi-ld/v1/entities", nil))
+
+			if delta := testutil.ToFloat64(counter) - before; delta != 1 {
+				t.Errorf("expected the request to be counted with code %s, got %v", tc.expected, delta)
+			}
+		})
+	}
+}
+
+func TestHandlerServesMetrics(t *testing.T) {
+	RecordDeadLetter("malformed")
+
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, name := range []string{`snowdepth_dead_letters_total{reason="malformed"}`, "go_goroutines", "process_cpu_seconds_total"} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("expected the metrics to contain %s", name)
+		}
+	}
+}
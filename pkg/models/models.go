@@ -1,6 +1,11 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/postgres"
 )
@@ -11,5 +16,249 @@ type Snowdepth struct {
 	Longitude float64
 	Device    string `gorm:"unique_index:idx_device_timestamp"`
 	Depth     float32
-	Timestamp string `gorm:"unique_index:idx_device_timestamp"`
+	Timestamp time.Time `gorm:"unique_index:idx_device_timestamp"`
+
+	// ChangedBy and ChangeReason are set when a measurement has been corrected or retracted
+	ChangedBy    string
+	ChangeReason string
+
+	// RawDepth is the value reported by the sensor, and CalibrationID is set when Depth has
+	// been computed from it with a device calibration
+	RawDepth      float32
+	CalibrationID *uint
+}
+
+// DeviceCalibration converts the raw values reported by a device to snow depths during the
+// time that it is valid. A nil ValidTo means that the calibration is valid until further notice.
+type DeviceCalibration struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Device    string
+	// MountingHeight is set for sensors that report the distance from the sensor to the surface
+	MountingHeight *float64
+	DepthOffset    float64
+	DepthScale     float64
+	ValidFrom      time.Time
+	ValidTo        *time.Time
+}
+
+// Covers reports whether the calibration is valid at the time t
+func (c DeviceCalibration) Covers(t time.Time) bool {
+	return !t.Before(c.ValidFrom) && (c.ValidTo == nil || t.Before(*c.ValidTo))
+}
+
+// Apply computes the snow depth from a raw value reported by the device
+func (c DeviceCalibration) Apply(raw float64) float64 {
+	if c.MountingHeight != nil {
+		raw = *c.MountingHeight - raw
+	}
+	return raw*c.DepthScale + c.DepthOffset
+}
+
+// DeviceHealth tracks when a device last reported a measurement and whether it reports as
+// often as expected. An empty Status means that the status has not been determined yet.
+type DeviceHealth struct {
+	Device   string `gorm:"primary_key"`
+	LastSeen time.Time
+	// ExpectedInterval is the number of seconds between reports from the device, or nil if
+	// the default interval applies
+	ExpectedInterval *int64
+	Status           string
+	StatusChangedAt  time.Time
+}
+
+// TableName returns the name of the device health table
+func (DeviceHealth) TableName() string {
+	return "device_health"
+}
+
+// AlertRule raises an alert when the depth at a device or a site crosses a threshold. The
+// rule is re-armed once the depth has moved back past the threshold by the hysteresis, and
+// no more than one alert is sent per cooldown period.
+type AlertRule struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	// Device and the site, a circle with a radius in meters, limit the measurements that the
+	// rule applies to. Rules without either apply to all measurements.
+	Device        *string
+	SiteLatitude  *float64
+	SiteLongitude *float64
+	SiteRadius    *float64
+	// Direction is either "above" or "below"
+	Direction  string
+	Threshold  float64
+	Hysteresis float64
+	// Cooldown is the minimum number of seconds between two alerts from the rule and device
+	Cooldown   int64
+	Enabled    bool
+	WebhookURL string
+}
+
+// AlertState is the state of an alert rule for a single device, or for manually added
+// measurements if the device is empty
+type AlertState struct {
+	RuleID        uint   `gorm:"primary_key;auto_increment:false"`
+	Device        string `gorm:"primary_key"`
+	Active        bool
+	LastTimestamp *time.Time
+	LastAlertAt   *time.Time
+}
+
+// OutboxEntry is a message that has been stored together with the change that it describes,
+// and that is removed once it has been published
+type OutboxEntry struct {
+	ID          uint64 `gorm:"primary_key"`
+	CreatedAt   time.Time
+	Topic       string
+	ContentType string
+	Body        string
+	// Webhook is set for alerts that are posted to the webhook of their rule instead of
+	// being published on the message bus
+	Webhook   string
+	Attempts  int
+	LastError string
+	// ClaimedUntil is set while a relay publishes the message, and other relays skip it
+	// until then
+	ClaimedUntil *time.Time
+}
+
+// TableName returns the name of the outbox table
+func (OutboxEntry) TableName() string {
+	return "outbox"
+}
+
+// DeadLetter is a received message that could not be stored, kept so that it can be
+// inspected and replayed
+type DeadLetter struct {
+	ID          uint64 `gorm:"primary_key"`
+	CreatedAt   time.Time
+	RoutingKey  string
+	ContentType string
+	Body        string
+	Reason      string
+	Error       string
+	Attempts    int
+	ReplayedAt  *time.Time
+}
+
+// TableName returns the name of the dead letter table
+func (DeadLetter) TableName() string {
+	return "dead_letters"
+}
+
+// SnowdepthStatistics contains aggregated snow depth values for a single device
+// during the time interval that begins at Start
+type SnowdepthStatistics struct {
+	Device string
+	Start  time.Time
+	Min    float64
+	Max    float64
+	Mean   float64
+	Count  int64
+	Last   float64
+}
+
+// SnowdepthAggregate contains the downsampled measurements from a single device and
+// position during the hour that begins at Start
+type SnowdepthAggregate struct {
+	ID            uint `gorm:"primary_key"`
+	Device        string
+	Start         time.Time
+	Latitude      float64
+	Longitude     float64
+	Min           float64
+	Max           float64
+	Sum           float64
+	Count         int64
+	Last          float64
+	LastTimestamp time.Time
+}
+
+// QuarantinedSnowdepth is a measurement that was rejected by a validation rule and is kept
+// for review instead of being stored
+type QuarantinedSnowdepth struct {
+	ID        uint `gorm:"primary_key"`
+	CreatedAt time.Time
+	Device    string
+	Latitude  float64
+	Longitude float64
+	Depth     float32
+	RawDepth  float32
+	Timestamp time.Time
+	Rule      string
+	Reason    string
+}
+
+// TableName returns the name of the quarantine table
+func (QuarantinedSnowdepth) TableName() string {
+	return "snowdepth_quarantine"
+}
+
+// SnowdepthAuditEntry records a manual write or an edit of a measurement, who made it and
+// from where. Entries are never changed once they have been added.
+type SnowdepthAuditEntry struct {
+	ID          uint `gorm:"primary_key"`
+	CreatedAt   time.Time
+	Operation   string
+	SnowdepthID uint
+	Actor       string
+	Identity    string
+	ClientIP    string
+	UserAgent   string
+	Before      *AuditedSnowdepth
+	After       *AuditedSnowdepth
+}
+
+// TableName returns the name of the audit table
+func (SnowdepthAuditEntry) TableName() string {
+	return "snowdepth_audit"
+}
+
+// AuditedSnowdepth contains the values of a measurement before or after a change. It is
+// stored as a JSON document.
+type AuditedSnowdepth struct {
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Depth     float32   `json:"depth"`
+	Timestamp time.Time `json:"timestamp"`
+	Retracted bool      `json:"retracted"`
+}
+
+// NewAuditedSnowdepth copies the audited values from a measurement, or returns nil if
+// there is no measurement
+func NewAuditedSnowdepth(s *Snowdepth) *AuditedSnowdepth {
+	if s == nil {
+		return nil
+	}
+
+	return &AuditedSnowdepth{
+		Latitude:  s.Latitude,
+		Longitude: s.Longitude,
+		Depth:     s.Depth,
+		Timestamp: s.Timestamp,
+		Retracted: s.DeletedAt != nil,
+	}
+}
+
+// Value implements driver.Valuer
+func (a AuditedSnowdepth) Value() (driver.Value, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (a *AuditedSnowdepth) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	}
+	return fmt.Errorf("can not scan %T into an AuditedSnowdepth", src)
 }
@@ -0,0 +1,193 @@
+// Package probes implements the liveness and readiness probes of the service. Readiness is
+// decided by running a set of checks against the components that the service depends on.
+package probes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Statuses of the service and of its components
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Config controls the readiness probe
+type Config struct {
+	// Timeout is the time that each check is allowed to take
+	Timeout time.Duration
+	// CheckContextSources adds checks for the remote NGSI context sources
+	CheckContextSources bool
+	// Checks are run in addition to the checks that are added by the handler
+	Checks []Check
+}
+
+// LoadConfig reads the readiness configuration from SNOWDEPTH_READY_TIMEOUT and
+// SNOWDEPTH_READY_CHECK_CONTEXT_SOURCES
+func LoadConfig() (Config, error) {
+	cfg := Config{Timeout: 2 * time.Second}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_READY_TIMEOUT"); ok {
+		if cfg.Timeout, err = time.ParseDuration(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_READY_TIMEOUT: %w", value, err)
+		}
+	}
+
+	if cfg.Timeout <= 0 {
+		return cfg, fmt.Errorf("SNOWDEPTH_READY_TIMEOUT must be positive")
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_READY_CHECK_CONTEXT_SOURCES"); ok {
+		if cfg.CheckContextSources, err = strconv.ParseBool(value); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_READY_CHECK_CONTEXT_SOURCES: %w", value, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Check verifies that a component is available
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// ComponentStatus is the result of a check
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the body of a probe response. Status is down if any of the components is down.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+// Run runs the checks concurrently, each with its own timeout, and reports their results
+func Run(ctx context.Context, timeout time.Duration, checks []Check) Report {
+	report := Report{Status: StatusUp, Components: map[string]ComponentStatus{}}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range checks {
+		wg.Add(1)
+
+		go func(check Check) {
+			defer wg.Done()
+
+			status := run(ctx, timeout, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Components[check.Name] = status
+			if status.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+func run(ctx context.Context, timeout time.Duration, check Check) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	status := ComponentStatus{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+
+	return status
+}
+
+// LivenessHandler reports that the process is up and able to serve requests. It does not
+// check any dependencies, so that the service is not restarted when one of them is down.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		write(w, Report{Status: StatusUp})
+	}
+}
+
+// ReadinessHandler runs the checks and responds with 200 OK if all of them succeed, or with
+// 503 Service Unavailable otherwise, together with the status and latency of every component
+func ReadinessHandler(cfg Config, checks []Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		write(w, Run(r.Context(), cfg.Timeout, checks))
+	}
+}
+
+func write(w http.ResponseWriter, report Report) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if report.Status != StatusUp {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.Write(body)
+}
+
+// HTTPCheck creates a check that succeeds if url responds to a GET request without a server
+// error. Any other response means that the server is reachable.
+func HTTPCheck(name, url string, client *http.Client) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return &StatusError{StatusCode: resp.StatusCode}
+			}
+
+			return nil
+		},
+	}
+}
+
+// StatusError is returned by an HTTP check when the server responds with an error
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return "responded with " + http.StatusText(e.StatusCode)
+}
@@ -0,0 +1,160 @@
+package probes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func succeeds(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+func fails(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return errors.New("unreachable") }}
+}
+
+func hangs(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         []Check
+		expectedCode   int
+		expectedStatus string
+		expectedDown   []string
+	}{
+		{"no checks", nil, http.StatusOK, StatusUp, nil},
+		{"all up", []Check{succeeds("database"), succeeds("messaging")}, http.StatusOK, StatusUp, nil},
+		{"one down", []Check{succeeds("database"), fails("messaging")}, http.StatusServiceUnavailable, StatusDown, []string{"messaging"}},
+		{"timed out", []Check{hangs("database"), succeeds("messaging")}, http.StatusServiceUnavailable, StatusDown, []string{"database"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ReadinessHandler(Config{Timeout: 10 * time.Millisecond}, tc.checks).ServeHTTP(w, httptest.NewRequest("GET", "/healthz/ready", nil))
+
+			if w.Code != tc.expectedCode {
+				t.Errorf("expected status code %d, got %d", tc.expectedCode, w.Code)
+			}
+			if w.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("expected the response not to be cached")
+			}
+
+			report := Report{}
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode report: %s", err)
+			}
+
+			if report.Status != tc.expectedStatus || len(report.Components) != len(tc.checks) {
+				t.Errorf("expected status %s with %d components, got %+v", tc.expectedStatus, len(tc.checks), report)
+			}
+
+			down := map[string]bool{}
+			for _, name := range tc.expectedDown {
+				down[name] = true
+			}
+			for name, component := range report.Components {
+				if down[name] != (component.Status == StatusDown) || down[name] != (component.Error != "") {
+					t.Errorf("expected %s to be down to be %t, got %+v", name, down[name], component)
+				}
+			}
+		})
+	}
+}
+
+func TestLivenessHandlerDoesNotRunChecks(t *testing.T) {
+	w := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(w, httptest.NewRequest("GET", "/healthz/live", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	report := Report{}
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil || report.Status != StatusUp || report.Components != nil {
+		t.Errorf("expected the process to be reported as up without components, got %s", w.Body.String())
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		fails  bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"not found is reachable", http.StatusNotFound, false},
+		{"server error", http.StatusBadGateway, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := HTTPCheck("remote", server.URL, server.Client()).Run(context.Background())
+
+			statusErr := &StatusError{}
+			if tc.fails && (!errors.As(err, &statusErr) || statusErr.StatusCode != tc.status) {
+				t.Errorf("expected the check to fail with status %d, got %v", tc.status, err)
+			} else if !tc.fails && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		fails    bool
+	}{
+		{name: "defaults", expected: Config{Timeout: 2 * time.Second}},
+		{
+			name:     "configured",
+			env:      map[string]string{"SNOWDEPTH_READY_TIMEOUT": "500ms", "SNOWDEPTH_READY_CHECK_CONTEXT_SOURCES": "true"},
+			expected: Config{Timeout: 500 * time.Millisecond, CheckContextSources: true},
+		},
+		{name: "no timeout", env: map[string]string{"SNOWDEPTH_READY_TIMEOUT": "0s"}, fails: true},
+		{name: "invalid flag", env: map[string]string{"SNOWDEPTH_READY_CHECK_CONTEXT_SOURCES": "sometimes"}, fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig()
+
+			if tc.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", cfg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if cfg.Timeout != tc.expected.Timeout || cfg.CheckContextSources != tc.expected.CheckContextSources {
+				t.Errorf("expected %+v, got %+v", tc.expected, cfg)
+			}
+		})
+	}
+}
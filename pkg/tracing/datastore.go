@@ -0,0 +1,223 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+	"github.com/diwise/api-snowdepth/pkg/models"
+)
+
+// tracedDatastore creates a span for every Datastore call, as a child of the span in the
+// context of the call. Calls that are not part of a trace, such as the periodic calls of
+// the background jobs, are not traced. Like the instrumented Datastore in the metrics
+// package, it does not embed the Datastore, so that new methods can not be added without
+// being traced.
+type tracedDatastore struct {
+	db database.Datastore
+}
+
+// NewTracedDatastore wraps a Datastore so that its calls are traced
+func NewTracedDatastore(db database.Datastore) database.Datastore {
+	return &tracedDatastore{db: db}
+}
+
+func start(ctx context.Context, method string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	if tenant, ok := database.TenantFromContext(ctx); ok {
+		attributes = append(attributes, attribute.String("snowdepth.tenant", tenant))
+	}
+	return tracer.Start(ctx, "Datastore."+method, trace.WithAttributes(attributes...))
+}
+
+func (d *tracedDatastore) AddManualSnowdepthMeasurement(ctx context.Context, latitude, longitude, depth float64) (m *models.Snowdepth, err error) {
+	ctx, span := start(ctx, "AddManualSnowdepthMeasurement")
+	defer func() { End(span, err) }()
+	return d.db.AddManualSnowdepthMeasurement(ctx, latitude, longitude, depth)
+}
+
+func (d *tracedDatastore) AddSnowdepthMeasurement(ctx context.Context, device *string, latitude, longitude, depth float64, when string) (m *models.Snowdepth, outcome database.IngestOutcome, err error) {
+	ctx, span := start(ctx, "AddSnowdepthMeasurement")
+	defer func() {
+		span.SetAttributes(attribute.String("snowdepth.outcome", outcome.String()))
+		End(span, err)
+	}()
+	return d.db.AddSnowdepthMeasurement(ctx, device, latitude, longitude, depth, when)
+}
+
+func (d *tracedDatastore) AddSnowdepthMeasurements(ctx context.Context, batch []database.NewMeasurement) (results []database.IngestResult, err error) {
+	ctx, span := start(ctx, "AddSnowdepthMeasurements", attribute.Int("snowdepth.batch_size", len(batch)))
+	defer func() { End(span, err) }()
+	return d.db.AddSnowdepthMeasurements(ctx, batch)
+}
+
+func (d *tracedDatastore) CorrectSnowdepthMeasurement(ctx context.Context, id uint, correction database.SnowdepthCorrection) (m *models.Snowdepth, err error) {
+	ctx, span := start(ctx, "CorrectSnowdepthMeasurement")
+	defer func() { End(span, err) }()
+	return d.db.CorrectSnowdepthMeasurement(ctx, id, correction)
+}
+
+func (d *tracedDatastore) RetractSnowdepthMeasurement(ctx context.Context, id uint, change database.MeasurementChange) (m *models.Snowdepth, err error) {
+	ctx, span := start(ctx, "RetractSnowdepthMeasurement")
+	defer func() { End(span, err) }()
+	return d.db.RetractSnowdepthMeasurement(ctx, id, change)
+}
+
+func (d *tracedDatastore) GetLatestSnowdepths(ctx context.Context) (snowdepths []models.Snowdepth, err error) {
+	ctx, span := start(ctx, "GetLatestSnowdepths")
+	defer func() { End(span, err) }()
+	return d.db.GetLatestSnowdepths(ctx)
+}
+
+func (d *tracedDatastore) GetLatestSnowdepthsForDevice(ctx context.Context, device string) (snowdepths []models.Snowdepth, err error) {
+	ctx, span := start(ctx, "GetLatestSnowdepthsForDevice")
+	defer func() { End(span, err) }()
+	return d.db.GetLatestSnowdepthsForDevice(ctx, device)
+}
+
+func (d *tracedDatastore) GetSnowdepthHistory(ctx context.Context, query database.SnowdepthQuery) (snowdepths []models.Snowdepth, err error) {
+	ctx, span := start(ctx, "GetSnowdepthHistory")
+	defer func() { End(span, err) }()
+	return d.db.GetSnowdepthHistory(ctx, query)
+}
+
+func (d *tracedDatastore) GetSnowdepthStatistics(ctx context.Context, device *string, from, to time.Time, interval database.AggregationInterval) (statistics []models.SnowdepthStatistics, err error) {
+	ctx, span := start(ctx, "GetSnowdepthStatistics")
+	defer func() { End(span, err) }()
+	return d.db.GetSnowdepthStatistics(ctx, device, from, to, interval)
+}
+
+func (d *tracedDatastore) GetAuditTrail(ctx context.Context, query database.AuditQuery) (entries []models.SnowdepthAuditEntry, err error) {
+	ctx, span := start(ctx, "GetAuditTrail")
+	defer func() { End(span, err) }()
+	return d.db.GetAuditTrail(ctx, query)
+}
+
+func (d *tracedDatastore) GetQuarantinedSnowdepths(ctx context.Context, query database.QuarantineQuery) (snowdepths []models.QuarantinedSnowdepth, err error) {
+	ctx, span := start(ctx, "GetQuarantinedSnowdepths")
+	defer func() { End(span, err) }()
+	return d.db.GetQuarantinedSnowdepths(ctx, query)
+}
+
+func (d *tracedDatastore) GetDeviceCalibrations(ctx context.Context, device string) (calibrations []models.DeviceCalibration, err error) {
+	ctx, span := start(ctx, "GetDeviceCalibrations")
+	defer func() { End(span, err) }()
+	return d.db.GetDeviceCalibrations(ctx, device)
+}
+
+func (d *tracedDatastore) AddDeviceCalibration(ctx context.Context, calibration database.Calibration, recompute bool) (change database.CalibrationChange, err error) {
+	ctx, span := start(ctx, "AddDeviceCalibration")
+	defer func() { End(span, err) }()
+	return d.db.AddDeviceCalibration(ctx, calibration, recompute)
+}
+
+func (d *tracedDatastore) UpdateDeviceCalibration(ctx context.Context, id uint, calibration database.Calibration, recompute bool) (change database.CalibrationChange, err error) {
+	ctx, span := start(ctx, "UpdateDeviceCalibration")
+	defer func() { End(span, err) }()
+	return d.db.UpdateDeviceCalibration(ctx, id, calibration, recompute)
+}
+
+func (d *tracedDatastore) DeleteDeviceCalibration(ctx context.Context, id uint, recompute bool) (change database.CalibrationChange, err error) {
+	ctx, span := start(ctx, "DeleteDeviceCalibration")
+	defer func() { End(span, err) }()
+	return d.db.DeleteDeviceCalibration(ctx, id, recompute)
+}
+
+func (d *tracedDatastore) GetDeviceHealth(ctx context.Context, device string) (health *models.DeviceHealth, err error) {
+	ctx, span := start(ctx, "GetDeviceHealth")
+	defer func() { End(span, err) }()
+	return d.db.GetDeviceHealth(ctx, device)
+}
+
+func (d *tracedDatastore) GetDevicesHealth(ctx context.Context, status *string) (health []models.DeviceHealth, err error) {
+	ctx, span := start(ctx, "GetDevicesHealth")
+	defer func() { End(span, err) }()
+	return d.db.GetDevicesHealth(ctx, status)
+}
+
+func (d *tracedDatastore) SetDeviceExpectedInterval(ctx context.Context, device string, interval *time.Duration) (health *models.DeviceHealth, err error) {
+	ctx, span := start(ctx, "SetDeviceExpectedInterval")
+	defer func() { End(span, err) }()
+	return d.db.SetDeviceExpectedInterval(ctx, device, interval)
+}
+
+func (d *tracedDatastore) UpdateDeviceStatuses(ctx context.Context, policy database.HealthPolicy) (result database.HealthResult, err error) {
+	ctx, span := start(ctx, "UpdateDeviceStatuses")
+	defer func() { End(span, err) }()
+	return d.db.UpdateDeviceStatuses(ctx, policy)
+}
+
+func (d *tracedDatastore) GetAlertRules(ctx context.Context) (rules []models.AlertRule, err error) {
+	ctx, span := start(ctx, "GetAlertRules")
+	defer func() { End(span, err) }()
+	return d.db.GetAlertRules(ctx)
+}
+
+func (d *tracedDatastore) AddAlertRule(ctx context.Context, rule database.AlertRuleInput) (added *models.AlertRule, err error) {
+	ctx, span := start(ctx, "AddAlertRule")
+	defer func() { End(span, err) }()
+	return d.db.AddAlertRule(ctx, rule)
+}
+
+func (d *tracedDatastore) UpdateAlertRule(ctx context.Context, id uint, rule database.AlertRuleInput) (updated *models.AlertRule, err error) {
+	ctx, span := start(ctx, "UpdateAlertRule")
+	defer func() { End(span, err) }()
+	return d.db.UpdateAlertRule(ctx, id, rule)
+}
+
+func (d *tracedDatastore) DeleteAlertRule(ctx context.Context, id uint) (deleted *models.AlertRule, err error) {
+	ctx, span := start(ctx, "DeleteAlertRule")
+	defer func() { End(span, err) }()
+	return d.db.DeleteAlertRule(ctx, id)
+}
+
+func (d *tracedDatastore) RelayOutbox(ctx context.Context, limit int, publish func(context.Context, database.OutboxMessage) error) (result database.OutboxResult, err error) {
+	ctx, span := start(ctx, "RelayOutbox")
+	defer func() { End(span, err) }()
+	return d.db.RelayOutbox(ctx, limit, publish)
+}
+
+func (d *tracedDatastore) AddDeadLetter(ctx context.Context, letter database.NewDeadLetter) (stored *models.DeadLetter, err error) {
+	ctx, span := start(ctx, "AddDeadLetter", attribute.String("snowdepth.dead_letter_reason", letter.Reason))
+	defer func() { End(span, err) }()
+	return d.db.AddDeadLetter(ctx, letter)
+}
+
+func (d *tracedDatastore) GetDeadLetters(ctx context.Context, query database.DeadLetterQuery) (letters []models.DeadLetter, err error) {
+	ctx, span := start(ctx, "GetDeadLetters")
+	defer func() { End(span, err) }()
+	return d.db.GetDeadLetters(ctx, query)
+}
+
+func (d *tracedDatastore) GetDeadLetter(ctx context.Context, id uint64) (letter *models.DeadLetter, err error) {
+	ctx, span := start(ctx, "GetDeadLetter")
+	defer func() { End(span, err) }()
+	return d.db.GetDeadLetter(ctx, id)
+}
+
+func (d *tracedDatastore) RecordDeadLetterReplay(ctx context.Context, id uint64, replayErr error) (letter *models.DeadLetter, err error) {
+	ctx, span := start(ctx, "RecordDeadLetterReplay")
+	defer func() { End(span, err) }()
+	return d.db.RecordDeadLetterReplay(ctx, id, replayErr)
+}
+
+func (d *tracedDatastore) ApplyRetention(ctx context.Context, policy database.RetentionPolicy) (result database.RetentionResult, err error) {
+	ctx, span := start(ctx, "ApplyRetention")
+	defer func() { End(span, err) }()
+	return d.db.ApplyRetention(ctx, policy)
+}
+
+func (d *tracedDatastore) Ping(ctx context.Context) (err error) {
+	ctx, span := start(ctx, "Ping")
+	defer func() { End(span, err) }()
+	return d.db.Ping(ctx)
+}
+
+func (d *tracedDatastore) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GraphQLExtension creates a span for every GraphQL operation, so that the spans of the
+// Datastore calls made by its resolvers are grouped by operation within the request
+type GraphQLExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = GraphQLExtension{}
+
+// ExtensionName returns the name of the extension
+func (GraphQLExtension) ExtensionName() string {
+	return "Tracing"
+}
+
+// Validate accepts any schema
+func (GraphQLExtension) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse starts a span that is named after the operation and ends it when the
+// response has been produced. The span is marked as failed if the response has errors.
+func (GraphQLExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+
+	operation, kind := "anonymous", "unknown"
+
+	if oc.OperationName != "" {
+		operation = oc.OperationName
+	}
+
+	if oc.Operation != nil {
+		kind = string(oc.Operation.Operation)
+	}
+
+	ctx, span := Start(ctx, "graphql "+kind+" "+operation,
+		trace.WithAttributes(
+			attribute.String("graphql.operation.name", operation),
+			attribute.String("graphql.operation.type", kind),
+		),
+	)
+
+	resp := next(ctx)
+
+	var err error
+	if resp == nil {
+		err = errors.New("no response")
+	} else if len(resp.Errors) > 0 {
+		err = resp.Errors
+	}
+
+	End(span, err)
+
+	return resp
+}
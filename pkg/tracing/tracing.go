@@ -0,0 +1,127 @@
+// Package tracing sets up OpenTelemetry tracing for the service, and creates the spans that
+// connect ingest, GraphQL and NGSI-LD requests with the Datastore calls they lead to
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters that spans can be sent to
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config controls where spans are exported and how many traces are sampled
+type Config struct {
+	Exporter    string
+	SampleRatio float64
+}
+
+// LoadConfig reads the tracing configuration from SNOWDEPTH_TRACING_EXPORTER and
+// SNOWDEPTH_TRACING_SAMPLE_RATIO. The OTLP exporter is configured with the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+func LoadConfig() (Config, error) {
+	cfg := Config{Exporter: ExporterNone, SampleRatio: 1}
+	var err error
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_TRACING_EXPORTER"); ok && value != "" {
+		cfg.Exporter = value
+	}
+
+	switch cfg.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+	default:
+		return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_TRACING_EXPORTER: must be one of none, otlp or stdout", cfg.Exporter)
+	}
+
+	if value, ok := os.LookupEnv("SNOWDEPTH_TRACING_SAMPLE_RATIO"); ok {
+		if cfg.SampleRatio, err = strconv.ParseFloat(value, 64); err != nil {
+			return cfg, fmt.Errorf("invalid value %q for SNOWDEPTH_TRACING_SAMPLE_RATIO: %w", value, err)
+		}
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return cfg, fmt.Errorf("SNOWDEPTH_TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	return cfg, nil
+}
+
+// Init installs the W3C trace context and baggage propagators, and a tracer provider that
+// exports spans as configured. The propagators are installed even when spans are not
+// exported, so that the trace context of incoming requests and messages is still passed
+// on to the messages and requests that the service sends. The returned function flushes
+// the remaining spans and should be called when the service shuts down.
+func Init(ctx context.Context, serviceName string, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceNameKey.String(serviceName)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+var tracer = otel.Tracer("github.com/diwise/api-snowdepth")
+
+// Start starts a span as a child of the span in ctx, if there is one
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, opts...)
+}
+
+// End marks the span as failed if err is set, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
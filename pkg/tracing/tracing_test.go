@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/diwise/api-snowdepth/pkg/database"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		fails    bool
+	}{
+		{name: "defaults", expected: Config{Exporter: ExporterNone, SampleRatio: 1}},
+		{
+			name:     "configured",
+			env:      map[string]string{"SNOWDEPTH_TRACING_EXPORTER": "otlp", "SNOWDEPTH_TRACING_SAMPLE_RATIO": "0.25"},
+			expected: Config{Exporter: ExporterOTLP, SampleRatio: 0.25},
+		},
+		{name: "unknown exporter", env: map[string]string{"SNOWDEPTH_TRACING_EXPORTER": "jaeger"}, fails: true},
+		{name: "ratio above one", env: map[string]string{"SNOWDEPTH_TRACING_SAMPLE_RATIO": "1.5"}, fails: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig()
+
+			if tc.fails {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", cfg)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if cfg != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestTracedDatastore(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	db := NewTracedDatastore(database.NewInMemoryDatastore(zerolog.Nop(), database.DuplicateIgnore, database.ValidationRules{}))
+
+	// Calls that are not part of a trace, such as those of the background jobs, are not traced
+	if _, err := db.GetLatestSnowdepths(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(recorder.Ended()) != 0 {
+		t.Fatalf("expected no spans outside of a trace, got %d", len(recorder.Ended()))
+	}
+
+	ctx, parent := Start(database.WithTenant(context.Background(), "skelleftea"), "request")
+
+	if _, err := db.GetLatestSnowdepths(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := db.GetDeviceHealth(ctx, "unknown"); !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("expected the device not to be found, got %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	tests := []struct {
+		name   string
+		status codes.Code
+	}{
+		{"Datastore.GetLatestSnowdepths", codes.Unset},
+		{"Datastore.GetDeviceHealth", codes.Error},
+	}
+
+	for idx, tc := range tests {
+		span := spans[idx]
+		if span.Name() != tc.name || span.Status().Code != tc.status {
+			t.Errorf("expected span %s with status %s, got %s with %s", tc.name, tc.status, span.Name(), span.Status().Code)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("expected span %s to be a child of the request", span.Name())
+		}
+
+		tenant := attribute.String("snowdepth.tenant", "skelleftea")
+		found := false
+		for _, a := range span.Attributes() {
+			found = found || a == tenant
+		}
+		if !found {
+			t.Errorf("expected span %s to record the tenant, got %v", span.Name(), span.Attributes())
+		}
+	}
+}